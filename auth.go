@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"strings"
+)
+
+// API_KEY_HEADER is the header (or gRPC metadata key) callers send their API key in.
+const API_KEY_HEADER = "X-API-Key"
+
+// apiKeyIsValid returns true if the key is one of config.APIKeys, or if no API keys are configured at all.
+func apiKeyIsValid(key string) bool {
+	if len(config.APIKeys) == 0 {
+		return true
+	}
+
+	valid := false
+	for _, apiKey := range config.APIKeys {
+		// Every key is compared (in constant time), so the time taken doesn't tell how much of a key was right.
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// APIKeyMiddleware is a validation middleware for ensuring that the caller sent a known API key in the X-API-Key
+// header, if any API keys are configured. If not, a HTTP 401 error response is sent back.
+func APIKeyMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !apiKeyIsValid(r.Header.Get(API_KEY_HEADER)) {
+			ReturnErrorResponse(w, r, http.StatusUnauthorized, ErrorResponse{Errors: []string{ERROR_BAD_API_KEY}})
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// grpcAPIKeyInterceptor is the gRPC equivalent of APIKeyMiddleware, with the API key sent in the "x-api-key"
+// metadata. Calls without a known API key fail with Unauthenticated.
+func grpcAPIKeyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	key := ""
+	if values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(API_KEY_HEADER)); len(values) > 0 {
+		key = values[0]
+	}
+
+	if !apiKeyIsValid(key) {
+		return nil, status.Error(codes.Unauthenticated, ERROR_BAD_API_KEY)
+	}
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// BREAKER_THRESHOLD is the number of requests to an upstream that have to fail in a row for its breaker to open.
+	BREAKER_THRESHOLD = 5
+	// BREAKER_COOLDOWN is how long a breaker stays open before a single request is let through to try the upstream again.
+	BREAKER_COOLDOWN = 30 * time.Second
+)
+
+const (
+	BREAKER_CLOSED    = "closed"
+	BREAKER_OPEN      = "open"
+	BREAKER_HALF_OPEN = "half_open"
+)
+
+// circuitBreaker stops requests to an upstream target after it failed BREAKER_THRESHOLD times in a row, so a broken
+// Overwatch site isn't hammered by (and doesn't hold up) every request. Once BREAKER_COOLDOWN has passed, a single trial
+// request is let through: if it succeeds the breaker closes again, otherwise it stays open for another cooldown.
+type circuitBreaker struct {
+	mutex    sync.Mutex
+	failures int
+	openedAt time.Time
+	trying   bool
+}
+
+// UPSTREAM_BREAKERS are the breakers of each upstream target.
+var UPSTREAM_BREAKERS = map[string]*circuitBreaker{
+	UPSTREAM_PROFILE:     {},
+	UPSTREAM_SEARCH:      {},
+	UPSTREAM_PATCH_NOTES: {},
+}
+
+// UpstreamUnavailableError is raised (as a panic) by fetchUpstream when the breaker of the upstream target is open.
+// RecoveryMiddleware turns it into a HTTP 503 error response.
+type UpstreamUnavailableError struct {
+	Target     string
+	RetryAfter time.Duration
+}
+
+func (e *UpstreamUnavailableError) Error() string {
+	return fmt.Sprintf("requests to the %s upstream are paused for %s after repeated failures", e.Target, e.RetryAfter.Round(time.Second))
+}
+
+// allow returns whether a request may be made to the upstream. If not, the time left until the next trial request is
+// returned as well.
+func (b *circuitBreaker) allow(now time.Time) (bool, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures < BREAKER_THRESHOLD {
+		return true, 0
+	}
+
+	if left := b.openedAt.Add(BREAKER_COOLDOWN).Sub(now); left > 0 {
+		return false, left
+	}
+
+	// Only one trial request is made at a time.
+	if b.trying {
+		return false, BREAKER_COOLDOWN
+	}
+
+	b.trying = true
+	return true, 0
+}
+
+// record records the result of a request allowed by allow.
+func (b *circuitBreaker) record(success bool, now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trying = false
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= BREAKER_THRESHOLD {
+		b.openedAt = now
+	}
+}
+
+// abandon records that a request allowed by allow was given up on by the caller, so it says nothing about the
+// upstream.
+func (b *circuitBreaker) abandon() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trying = false
+}
+
+// state returns BREAKER_CLOSED, BREAKER_OPEN or BREAKER_HALF_OPEN (when the cooldown has passed, but the upstream
+// hasn't been tried again yet).
+func (b *circuitBreaker) state(now time.Time) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch {
+	case b.failures < BREAKER_THRESHOLD:
+		return BREAKER_CLOSED
+	case now.Before(b.openedAt.Add(BREAKER_COOLDOWN)):
+		return BREAKER_OPEN
+	}
+	return BREAKER_HALF_OPEN
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// responseCache caches the bodies of the responses of the Overwatch site, keyed by URL, so repeated requests for the
+// same player don't each fetch the same pages. Each upstream target has its own TTL (see CacheConfig).
+// If a storage path is set, the bodies are also written there, so they survive a restart.
+type responseCache struct {
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+var upstreamCache = &responseCache{entries: map[string]cacheEntry{}}
+
+// cacheTTL returns how long responses of the given upstream target are cached for. 0 means they aren't cached.
+func cacheTTL(target string) time.Duration {
+	switch target {
+	case UPSTREAM_PROFILE:
+		return config.Cache.ProfileTTL
+	case UPSTREAM_SEARCH:
+		return config.Cache.SearchTTL
+	case UPSTREAM_PATCH_NOTES:
+		return config.Cache.PatchNotesTTL
+	}
+	return 0
+}
+
+// get returns the cached body of the URL, if there is one that hasn't expired.
+// Bodies missing from memory are read from storage (if set), and kept in memory for the rest of their TTL.
+// The mutex is only held to use the map, so reading from storage doesn't hold up other requests.
+func (c *responseCache) get(target string, url string) ([]byte, bool) {
+	ttl := cacheTTL(target)
+	if ttl <= 0 {
+		return nil, false
+	}
+
+	now := time.Now()
+
+	c.mutex.Lock()
+	entry, ok := c.entries[url]
+	if ok && !now.Before(entry.expires) {
+		delete(c.entries, url)
+		ok = false
+	}
+	c.mutex.Unlock()
+
+	if ok {
+		return entry.body, true
+	}
+
+	if config.Storage.Path == "" {
+		return nil, false
+	}
+
+	path := storagePath(url)
+	info, err := os.Stat(path)
+	if err != nil || !now.Before(info.ModTime().Add(ttl)) {
+		return nil, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	c.add(url, cacheEntry{body, info.ModTime().Add(ttl)})
+	return body, true
+}
+
+// set caches the body of the URL for the TTL of its upstream target, writing it to storage (if set) as well.
+func (c *responseCache) set(target string, url string, body []byte) {
+	ttl := cacheTTL(target)
+	if ttl <= 0 {
+		return
+	}
+
+	c.add(url, cacheEntry{body, time.Now().Add(ttl)})
+
+	if config.Storage.Path != "" {
+		if err := writeStorage(storagePath(url), body); err != nil {
+			logger.Warn("writing cache to storage", "target", target, "error", err.Error())
+		}
+	}
+}
+
+// add adds the entry, making room for it first if the cache is full: expired entries are removed, and then (if it is
+// still full) the entry that expires first. The removed entries are removed from storage (if set) as well, so storage
+// never holds more than config.Cache.MaxEntries responses that are still cached.
+func (c *responseCache) add(url string, entry cacheEntry) {
+	removed := c.addEntry(url, entry)
+
+	if config.Storage.Path != "" {
+		for _, u := range removed {
+			if err := os.Remove(storagePath(u)); err != nil && !os.IsNotExist(err) {
+				logger.Warn("removing cache from storage", "error", err.Error())
+			}
+		}
+	}
+}
+
+// addEntry adds the entry to the map, making room for it as described by add, and returns the URLs of the entries
+// removed to make room.
+func (c *responseCache) addEntry(url string, entry cacheEntry) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var removed []string
+	if _, ok := c.entries[url]; !ok && len(c.entries) >= config.Cache.MaxEntries {
+		now := time.Now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+				removed = append(removed, k)
+			}
+		}
+
+		if len(c.entries) >= config.Cache.MaxEntries {
+			var first string
+			for k, e := range c.entries {
+				if first == "" || e.expires.Before(c.entries[first].expires) {
+					first = k
+				}
+			}
+			delete(c.entries, first)
+			removed = append(removed, first)
+		}
+	}
+
+	c.entries[url] = entry
+	return removed
+}
+
+// len returns the number of responses cached in memory.
+func (c *responseCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.entries)
+}
+
+// STORAGE_SWEEP_INTERVAL is how often sweepStorage removes expired responses from storage.
+const STORAGE_SWEEP_INTERVAL = 10 * time.Minute
+
+// sweepStorage removes the responses in storage that have expired for every upstream target, every
+// STORAGE_SWEEP_INTERVAL, until the context is done. add only removes the responses that are also in memory, so this
+// catches the rest (i.e. those written before a restart, and never requested since).
+func sweepStorage(ctx context.Context) {
+	ticker := time.NewTicker(STORAGE_SWEEP_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		removeExpiredStorage(time.Now())
+	}
+}
+
+// removeExpiredStorage removes the files in storage that are older than the longest TTL, so they can't be the
+// cached response of any upstream target.
+func removeExpiredStorage(now time.Time) {
+	ttl := max(config.Cache.ProfileTTL, config.Cache.SearchTTL, config.Cache.PatchNotesTTL)
+
+	entries, err := os.ReadDir(config.Storage.Path)
+	if err != nil {
+		logger.Warn("sweeping cache storage", "error", err.Error())
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || now.Before(info.ModTime().Add(ttl)) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(config.Storage.Path, entry.Name())); err != nil && !os.IsNotExist(err) {
+			logger.Warn("removing cache from storage", "error", err.Error())
+		}
+	}
+}
+
+// storagePath returns the path of the file the body of the URL is stored in.
+func storagePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(config.Storage.Path, hex.EncodeToString(sum[:]))
+}
+
+// writeStorage writes the body to the path, through a temporary file, so a concurrent read never sees half of it.
+func writeStorage(path string, body []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// fetchUpstream returns the body of the response to a GET request to the Overwatch site, made on behalf of the request
+// the context belongs to. Responses are cached (see responseCache), and only fetched if they aren't.
+// Panics if the request fails (or the upstream responds with a HTTP 5xx error), which RecoveryMiddleware turns into a
+// HTTP 500 error response. Failures are counted by the upstream's circuitBreaker; while it is open, no request is made
+// and an *UpstreamUnavailableError is raised instead.
+func fetchUpstream(ctx context.Context, target string, url string) []byte {
+	// Lookups are only counted if the target is cached at all.
+	if cacheTTL(target) > 0 {
+		body, ok := upstreamCache.get(target, url)
+		observeCache(ctx, target, ok)
+		if ok {
+			return body
+		}
+	}
+
+	breaker := UPSTREAM_BREAKERS[target]
+	if ok, retryAfter := breaker.allow(time.Now()); !ok {
+		panic(&UpstreamUnavailableError{Target: target, RetryAfter: retryAfter})
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		breaker.abandon()
+		panic(err)
+	}
+
+	start := time.Now()
+	res, err := upstreamClient().Do(req)
+	if err == nil && res.StatusCode >= 500 {
+		res.Body.Close()
+		err = fmt.Errorf("the %s upstream responded with HTTP %d", target, res.StatusCode)
+	}
+
+	observeUpstream(ctx, target, start, err)
+	if err != nil {
+		// A request the caller gave up on says nothing about the upstream.
+		if ctx.Err() != nil {
+			breaker.abandon()
+		} else {
+			breaker.record(false, time.Now())
+		}
+		panic(err)
+	}
+	defer res.Body.Close()
+	breaker.record(true, time.Now())
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	// Only successful responses are cached, so an error page isn't served for the whole TTL.
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		upstreamCache.set(target, url, body)
+	}
+
+	return body
+}
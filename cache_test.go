@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCacheStorageIsLimited(t *testing.T) {
+	cacheConfig, storageConfig := config.Cache, config.Storage
+	t.Cleanup(func() { config.Cache, config.Storage = cacheConfig, storageConfig })
+
+	config.Cache.MaxEntries = 1
+	config.Storage.Path = t.TempDir()
+	cache := &responseCache{entries: map[string]cacheEntry{}}
+
+	cache.set(UPSTREAM_PROFILE, "https://example.com/a", []byte("a"))
+	cache.set(UPSTREAM_PROFILE, "https://example.com/b", []byte("b"))
+
+	// "a" was evicted to make room for "b", so it is removed from storage as well.
+	if _, err := os.Stat(storagePath("https://example.com/a")); !os.IsNotExist(err) {
+		t.Errorf("got %v for the evicted response, want it removed from storage", err)
+	}
+
+	if body, ok := cache.get(UPSTREAM_PROFILE, "https://example.com/b"); !ok || string(body) != "b" {
+		t.Errorf("got %q, %v for the cached response, want %q, true", body, ok, "b")
+	}
+
+	// Once every TTL has passed, the sweep removes what is left.
+	removeExpiredStorage(time.Now().Add(max(config.Cache.ProfileTTL, config.Cache.SearchTTL, config.Cache.PatchNotesTTL) + time.Minute))
+
+	entries, err := os.ReadDir(config.Storage.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d files in storage after the sweep, want 0", len(entries))
+	}
+}
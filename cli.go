@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is the tabular (human readable) output of a command.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// cliCommand is a subcommand of the command-line tool.
+type cliCommand struct {
+	name    string
+	args    []string // Names of the positional arguments, in order.
+	summary string
+
+	// player commands take --platform and --region, and check that the player exists before running.
+	// mode commands also take --mode.
+	player bool
+	mode   bool
+
+	// run returns the result (encoded as is for JSON output) and its table.
+	run func(p *Player, mode string, args []string) (interface{}, table, error)
+}
+
+// CLI_COMMANDS are the subcommands of the command-line tool, in the order they are listed in the usage.
+var CLI_COMMANDS = []cliCommand{
+	{"search", []string{"tag"}, "List every platform, region and tag combination matching the tag.", false, false, runSearch},
+	{"profile", []string{"tag"}, "Show the player's profile overview.", true, false, runProfile},
+	{"achievements", []string{"tag"}, "List every achievement, and whether the player finished it.", true, false, runAchievements},
+	{"stats", []string{"tag"}, "Show the stats of all heroes combined.", true, true, runStats},
+	{"hero", []string{"tag", "hero"}, "Show the stats of a single hero.", true, true, runHero},
+	{"breakdown", []string{"tag"}, "Show each stat broken down by hero.", true, true, runBreakdown},
+	{"patch-notes", nil, "List the latest patch notes.", false, false, runPatchNotes},
+	{"parse", []string{"file"}, "Parse a saved career page (\"-\" for stdin) without fetching anything.", false, false, runParse},
+}
+
+// runCLI runs the command-line tool with the given arguments (without the program name), and returns the exit code.
+// The scraping core is called directly, so the server doesn't need to be running.
+func runCLI(args []string, stdout io.Writer, stderr io.Writer) (code int) {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	var cmd *cliCommand
+	for i := range CLI_COMMANDS {
+		if CLI_COMMANDS[i].name == args[0] {
+			cmd = &CLI_COMMANDS[i]
+		}
+	}
+
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: goverwatch %s [flags] %s\n\n%s\n\nflags:\n", cmd.name, cmd.argsUsage(), cmd.summary)
+		flags.PrintDefaults()
+	}
+
+	output := flags.String("output", "table", "output format: table or json")
+
+	// Only the flags the command takes are registered, so the usage doesn't list the others.
+	platform, region, mode := "pc", "us", "quickplay"
+	if cmd.player {
+		flags.StringVar(&platform, "platform", platform, "platform: pc, psn or xbl")
+		flags.StringVar(&region, "region", region, "region: us, eu, cn, kr or global")
+	}
+	if cmd.mode {
+		flags.StringVar(&mode, "mode", mode, "mode: quickplay or competitive")
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if flags.NArg() != len(cmd.args) {
+		flags.Usage()
+		return 2
+	}
+
+	p := &Player{
+		Platform: strings.ToLower(platform),
+		Region:   strings.ToLower(region),
+		Tag:      flags.Arg(0),
+		ctx:      context.WithValue(context.Background(), requestInfoKey{}, &requestInfo{}),
+	}
+
+	errors := []string{}
+	if !OUTPUTS[*output] {
+		errors = append(errors, ERROR_BAD_OUTPUT)
+	}
+
+	if cmd.player && !p.platformIsValid() {
+		errors = append(errors, ERROR_BAD_PLATFORM)
+	}
+
+	if cmd.player && !p.regionIsValid() {
+		errors = append(errors, ERROR_BAD_REGION)
+	}
+
+	if cmd.mode && !modeIsValid(mode) {
+		errors = append(errors, ERROR_BAD_MODE)
+	}
+
+	if len(errors) > 0 {
+		for _, e := range errors {
+			fmt.Fprintln(stderr, e)
+		}
+		return 2
+	}
+
+	// Anything the scraping core logs (i.e. unknown stat labels, or the Overwatch site failing) goes to stderr as well,
+	// so the output is the only thing written to stdout.
+	defer func(l *slog.Logger) { logger = l }(logger)
+	logger = slog.New(slog.NewJSONHandler(stderr, nil))
+
+	// Anything the scraping core panics with (i.e. the Overwatch site being unreachable) is reported as an error.
+	defer func() {
+		if err := recover(); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			code = 1
+		}
+	}()
+
+	if cmd.player && len(p.GetAccountByName()) == 0 {
+		fmt.Fprintln(stderr, ERROR_PLAYER_NOT_FOUND)
+		return 1
+	}
+
+	res, t, err := cmd.run(p, strings.ToLower(mode), flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	for _, warning := range parseWarningsFromContext(p.context()) {
+		fmt.Fprintln(stderr, "warning:", warning)
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(res); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	printTable(stdout, t)
+	return 0
+}
+
+func (cmd *cliCommand) argsUsage() string {
+	args := []string{}
+	for _, arg := range cmd.args {
+		args = append(args, "<"+arg+">")
+	}
+	return strings.Join(args, " ")
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: goverwatch [command] [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, the API server is started.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range CLI_COMMANDS {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.argsUsage(), cmd.summary)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run \"goverwatch [command] -h\" for the flags of a command.")
+}
+
+func printTable(w io.Writer, t table) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+func statsTable(stats []Stat) table {
+	t := table{headers: []string{"SECTION", "NAME", "VALUE"}}
+	for _, stat := range stats {
+		t.rows = append(t.rows, []string{stat.SectionName, stat.Name, stat.Value})
+	}
+	return t
+}
+
+func runSearch(p *Player, mode string, args []string) (interface{}, table, error) {
+	results := GetSearchResults(p.GetAccountByName())
+
+	t := table{headers: []string{"PLATFORM", "REGION", "TAG"}}
+	for _, result := range results {
+		t.rows = append(t.rows, []string{result.Platform, result.Region, result.Tag})
+	}
+
+	return results, t, nil
+}
+
+func runProfile(p *Player, mode string, args []string) (interface{}, table, error) {
+	d := p.GetProfileDoc()
+	CheckLayout(p.context(), d, PARSER_PROFILE, "")
+
+	profile := GetProfile(d, p, p.GetMatchingAccount())
+
+	modeSummary := func(m Mode) string {
+		return fmt.Sprintf("%d won, %d lost, %d played (%s)", m.Won, m.Lost, m.Played, m.Time)
+	}
+
+	t := table{
+		headers: []string{"FIELD", "VALUE"},
+		rows: [][]string{
+			{"Username", profile.Username},
+			{"Level", strconv.Itoa(profile.Level.Actual)},
+			{"Stars", strconv.Itoa(profile.Level.Stars)},
+			{"Tier", profile.Level.Tier},
+			{"Quickplay", modeSummary(profile.Modes.Quickplay)},
+			{"Competitive", modeSummary(profile.Modes.Competitive)},
+			{"Rank", rankSummary(profile.Competitive)},
+		},
+	}
+
+	if profile.Competitive != nil {
+		for _, role := range profile.Competitive.Roles {
+			t.rows = append(t.rows, []string{"Rank (" + role.Role + ")", fmt.Sprintf("%d (%s)", role.SkillRating, role.Tier)})
+		}
+	}
+
+	return profile, t, nil
+}
+
+// rankSummary returns the skill rating and tier of the rank, or "unranked" if rank is nil.
+func rankSummary(rank *CompetitiveRank) string {
+	if rank == nil {
+		return "unranked"
+	}
+	return fmt.Sprintf("%d (%s)", rank.SkillRating, rank.Tier)
+}
+
+func runAchievements(p *Player, mode string, args []string) (interface{}, table, error) {
+	d := p.GetProfileDoc()
+	CheckLayout(p.context(), d, PARSER_ACHIEVEMENTS, "")
+
+	achievements := GetAchievements(d)
+
+	t := table{headers: []string{"TITLE", "FINISHED", "DESCRIPTION"}}
+	for _, achievement := range achievements {
+		t.rows = append(t.rows, []string{achievement.Title, strconv.FormatBool(achievement.Finished), achievement.Description})
+	}
+
+	return achievements, t, nil
+}
+
+func runStats(p *Player, mode string, args []string) (interface{}, table, error) {
+	d := p.GetProfileDoc()
+	CheckLayout(p.context(), d, PARSER_ALL_HERO_STATS, mode)
+
+	stats := GetAllHeroStats(d, mode)
+	return stats, statsTable(stats), nil
+}
+
+func runHero(p *Player, mode string, args []string) (interface{}, table, error) {
+	d := p.GetProfileDoc()
+	CheckLayout(p.context(), d, PARSER_HERO_STATS, mode)
+
+	hex, ok := GetHeroHexMap(d)[strings.ToLower(args[1])]
+	if !ok {
+		return nil, table{}, errors.New(ERROR_HERO_NOT_FOUND)
+	}
+
+	stats := GetHeroStats(d, mode, hex)
+	return stats, statsTable(stats), nil
+}
+
+func runBreakdown(p *Player, mode string, args []string) (interface{}, table, error) {
+	d := p.GetProfileDoc()
+	CheckLayout(p.context(), d, PARSER_HEROS_BREAKDOWN, mode)
+
+	breakdowns := GetHeroBreakdowns(d, mode)
+
+	names := []string{}
+	for name := range breakdowns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := table{headers: []string{"STAT", "HERO", "VALUE", "PERCENTAGE"}}
+	for _, name := range names {
+		for _, breakdown := range breakdowns[name] {
+			t.rows = append(t.rows, []string{name, breakdown.Hero, breakdown.Value, strconv.FormatFloat(breakdown.Percentage, 'f', -1, 64)})
+		}
+	}
+
+	return breakdowns, t, nil
+}
+
+func runPatchNotes(p *Player, mode string, args []string) (interface{}, table, error) {
+	notes := GetPatchNotes(p.context())
+
+	t := table{headers: []string{"VERSION", "BUILD", "PUBLISHED"}}
+	for _, note := range notes {
+		t.rows = append(t.rows, []string{note.Version, strconv.Itoa(note.BuildNumber), note.Published})
+	}
+
+	return notes, t, nil
+}
+
+func runParse(p *Player, mode string, args []string) (interface{}, table, error) {
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, table{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	d, err := ParseCareerPage(r)
+	if err != nil {
+		return nil, table{}, err
+	}
+
+	export := GetOfflineExport(p.context(), d)
+
+	// The table only summarizes what was found, use JSON output for everything.
+	finished := 0
+	for _, achievement := range export.Achievements {
+		if achievement.Finished {
+			finished++
+		}
+	}
+
+	t := table{
+		headers: []string{"FIELD", "VALUE"},
+		rows: [][]string{
+			{"Username", export.Profile.Username},
+			{"Level", export.Profile.Level.Displayed},
+			{"Rank", rankSummary(export.Profile.Competitive)},
+			{"Achievements", fmt.Sprintf("%d (%d finished)", len(export.Achievements), finished)},
+		},
+	}
+
+	for _, mode := range sortedKeys(MODES) {
+		m := export.Modes[mode]
+		t.rows = append(t.rows, []string{mode, fmt.Sprintf("%d stats, %d breakdowns, %d heroes", len(m.AllHeroStats), len(m.HerosBreakdown), len(m.Heroes))})
+	}
+
+	return export, t, nil
+}
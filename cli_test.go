@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCLIJSONOutput(t *testing.T) {
+	useTestUpstream(t)
+
+	// "Blaster Kills" is an unknown stat label, which is logged.
+	resetUnknownStatLabels()
+
+	var stdout, stderr bytes.Buffer
+	if code := runCLI([]string{"hero", "--output", "json", "Tester-1234", "mercy"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("got exit code %d, want 0: %s", code, stderr.String())
+	}
+
+	var stats []Stat
+	if err := json.Unmarshal(stdout.Bytes(), &stats); err != nil {
+		t.Fatalf("stdout isn't only the JSON output: %v\n%s", err, stdout.String())
+	}
+
+	if len(stats) != 1 || stats[0].Key != "blaster_kills" {
+		t.Errorf("got stats %+v, want blaster_kills", stats)
+	}
+
+	if !strings.Contains(stderr.String(), "unknown stat label") {
+		t.Errorf("got stderr %q, want the unknown stat label logged", stderr.String())
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type ServerConfig struct {
+	Address         string        `yaml:"address"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// TLS is only used if both the cert and key file are set.
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+
+	// GRPCAddress is the address the gRPC service listens on. The gRPC service is disabled if it isn't set.
+	GRPCAddress string `yaml:"grpc_address"`
+}
+
+type UpstreamConfig struct {
+	BaseURL      string        `yaml:"base_url"`
+	SearchURL    string        `yaml:"search_url"`
+	PatchNoteURL string        `yaml:"patch_note_url"`
+	Timeout      time.Duration `yaml:"timeout"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+}
+
+// CacheConfig sets how long the responses of each upstream are cached for (see responseCache). A TTL of 0 turns
+// caching off for that upstream.
+type CacheConfig struct {
+	ProfileTTL    time.Duration `yaml:"profile_ttl"`
+	SearchTTL     time.Duration `yaml:"search_ttl"`
+	PatchNotesTTL time.Duration `yaml:"patch_notes_ttl"`
+
+	// MaxEntries is the most responses kept in memory.
+	MaxEntries int `yaml:"max_entries"`
+}
+
+type RateLimitConfig struct {
+	// RequestsPerMinute is how many API (and GraphQL/gRPC) requests each client (by IP address) can make per minute.
+	// Nothing is limited if it is 0.
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+}
+
+type StorageConfig struct {
+	// Path is the directory cached responses are written to, so they survive a restart. They are only kept in memory
+	// if it isn't set.
+	// Responses are removed from it when they are evicted from memory or have expired (see responseCache.add and
+	// sweepStorage).
+	Path string `yaml:"path"`
+}
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Upstream  UpstreamConfig  `yaml:"upstream"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Storage   StorageConfig   `yaml:"storage"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// APIKeys are the keys callers must send (in the X-API-Key header) to use the API, GraphQL and gRPC. Anyone can
+	// use them if it is empty.
+	APIKeys []string `yaml:"api_keys"`
+
+	CORS      CORSConfig `yaml:"cors"`
+	Selectors Selectors  `yaml:"selectors"`
+}
+
+// config is the configuration the service is running with.
+// It starts out as the defaults, and is replaced by loadConfig at startup.
+var config = defaultConfig()
+
+// defaultConfig returns the configuration used for any setting that isn't set in the config file or the environment.
+func defaultConfig() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:         ":8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Upstream: UpstreamConfig{
+			BaseURL:      BASE_URL,
+			SearchURL:    SEARCH_URL,
+			PatchNoteURL: PATCH_NOTE_URL,
+			Timeout:      30 * time.Second,
+		},
+		Cache: CacheConfig{
+			ProfileTTL:    time.Minute,
+			SearchTTL:     time.Minute,
+			PatchNotesTTL: time.Hour,
+			MaxEntries:    1000,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions},
+			AllowedHeaders: []string{"Accept", "Content-Type", REQUEST_ID_HEADER, API_KEY_HEADER},
+			ExposedHeaders: []string{REQUEST_ID_HEADER, PARSE_WARNINGS_HEADER},
+		},
+		Selectors: defaultSelectors(),
+	}
+}
+
+// loadConfig returns the configuration, built from the defaults, the YAML config file at the given path (if any) and
+// then the environment, in that order.
+// The configuration is validated, and an error listing every invalid setting is returned if it isn't valid.
+func loadConfig(path string) (Config, error) {
+	c := defaultConfig()
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return c, fmt.Errorf("config: %v", err)
+		}
+		defer f.Close()
+
+		// Unknown keys are an error rather than ignored, so a misspelled (or renamed) setting doesn't silently fall
+		// back to its default. An empty file has no settings.
+		decoder := yaml.NewDecoder(f)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&c); err != nil && err != io.EOF {
+			return c, fmt.Errorf("config: %s: %v", path, err)
+		}
+	}
+
+	if err := c.applyEnv(); err != nil {
+		return c, err
+	}
+
+	return c, c.validate()
+}
+
+// reloadOnSIGHUP reloads the config file at the given path every time the process receives SIGHUP.
+// Only the selectors are reloaded, since every other setting is only used at startup. If the config file isn't valid,
+// the error is logged and the current selectors are kept. Otherwise the unknown stat labels that have been logged are
+// forgotten, so the labels still missing with the new selectors are logged again.
+// Runs until the context is done.
+func reloadOnSIGHUP(ctx context.Context, path string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		}
+
+		c, err := loadConfig(path)
+		if err != nil {
+			logger.Error("reloading config", "path", path, "error", err.Error())
+			continue
+		}
+
+		setSelectors(c.Selectors)
+		resetUnknownStatLabels()
+		logger.Info("reloaded config", "path", path)
+	}
+}
+
+// applyEnv overrides the settings that are set in the environment.
+// Timeouts use the format of time.ParseDuration (i.e. "30s", "2m").
+func (c *Config) applyEnv() error {
+	// PORT is kept for backwards compatibility, LISTEN_ADDR takes precedence over it.
+	if port := os.Getenv("PORT"); port != "" {
+		c.Server.Address = ":" + port
+	}
+
+	values := map[string]*string{
+		"LISTEN_ADDR":    &c.Server.Address,
+		"TLS_CERT_FILE":  &c.Server.TLSCertFile,
+		"TLS_KEY_FILE":   &c.Server.TLSKeyFile,
+		"GRPC_ADDR":      &c.Server.GRPCAddress,
+		"BASE_URL":       &c.Upstream.BaseURL,
+		"SEARCH_URL":     &c.Upstream.SearchURL,
+		"PATCH_NOTE_URL": &c.Upstream.PatchNoteURL,
+		"STORAGE_PATH":   &c.Storage.Path,
+	}
+
+	for name, v := range values {
+		if value := os.Getenv(name); value != "" {
+			*v = value
+		}
+	}
+
+	// Lists are comma separated (i.e. "https://a.example.com,https://b.example.com").
+	lists := map[string]*[]string{
+		"CORS_ALLOWED_ORIGINS": &c.CORS.AllowedOrigins,
+		"CORS_ALLOWED_METHODS": &c.CORS.AllowedMethods,
+		"CORS_ALLOWED_HEADERS": &c.CORS.AllowedHeaders,
+		"CORS_EXPOSED_HEADERS": &c.CORS.ExposedHeaders,
+		"API_KEYS":             &c.APIKeys,
+	}
+
+	for name, list := range lists {
+		if value := os.Getenv(name); value != "" {
+			*list = splitList(value)
+		}
+	}
+
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("config: invalid CORS_ALLOW_CREDENTIALS %q: %v", value, err)
+		}
+		c.CORS.AllowCredentials = allow
+	}
+
+	durations := map[string]*time.Duration{
+		"READ_TIMEOUT":     &c.Server.ReadTimeout,
+		"WRITE_TIMEOUT":    &c.Server.WriteTimeout,
+		"IDLE_TIMEOUT":     &c.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT": &c.Server.ShutdownTimeout,
+		"UPSTREAM_TIMEOUT": &c.Upstream.Timeout,
+
+		"CACHE_PROFILE_TTL":     &c.Cache.ProfileTTL,
+		"CACHE_SEARCH_TTL":      &c.Cache.SearchTTL,
+		"CACHE_PATCH_NOTES_TTL": &c.Cache.PatchNotesTTL,
+	}
+
+	for name, duration := range durations {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("config: invalid %s %q: %v", name, value, err)
+		}
+		*duration = d
+	}
+
+	ints := map[string]*int{
+		"CACHE_MAX_ENTRIES":              &c.Cache.MaxEntries,
+		"RATE_LIMIT_REQUESTS_PER_MINUTE": &c.RateLimit.RequestsPerMinute,
+	}
+
+	for name, i := range ints {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("config: invalid %s %q: %v", name, value, err)
+		}
+		*i = n
+	}
+
+	return nil
+}
+
+// validate returns an error listing every invalid setting, or nil if the configuration is valid.
+func (c *Config) validate() error {
+	var errs []error
+
+	if c.Server.Address == "" {
+		errs = append(errs, errors.New("config: server.address must be set"))
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"upstream.timeout", c.Upstream.Timeout},
+	}
+
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("config: %s must be positive, got %s", timeout.name, timeout.value))
+		}
+	}
+
+	ttls := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"cache.profile_ttl", c.Cache.ProfileTTL},
+		{"cache.search_ttl", c.Cache.SearchTTL},
+		{"cache.patch_notes_ttl", c.Cache.PatchNotesTTL},
+	}
+
+	for _, ttl := range ttls {
+		if ttl.value < 0 {
+			errs = append(errs, fmt.Errorf("config: %s must not be negative, got %s", ttl.name, ttl.value))
+		}
+	}
+
+	if c.Cache.MaxEntries <= 0 {
+		errs = append(errs, fmt.Errorf("config: cache.max_entries must be positive, got %d", c.Cache.MaxEntries))
+	}
+
+	if c.RateLimit.RequestsPerMinute < 0 {
+		errs = append(errs, fmt.Errorf("config: rate_limit.requests_per_minute must not be negative, got %d",
+			c.RateLimit.RequestsPerMinute))
+	}
+
+	if slices.Contains(c.APIKeys, "") {
+		errs = append(errs, errors.New("config: api_keys must not contain an empty key"))
+	}
+
+	if c.Storage.Path != "" {
+		if info, err := os.Stat(c.Storage.Path); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("config: storage.path must be an existing directory, got %q", c.Storage.Path))
+		}
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("config: server.tls_cert_file and server.tls_key_file must be set together"))
+	}
+
+	urls := []struct {
+		name  string
+		value string
+	}{
+		{"upstream.base_url", c.Upstream.BaseURL},
+		{"upstream.search_url", c.Upstream.SearchURL},
+		{"upstream.patch_note_url", c.Upstream.PatchNoteURL},
+	}
+
+	for _, u := range urls {
+		parsed, err := url.Parse(u.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("config: %s must be an absolute http(s) URL, got %q", u.name, u.value))
+		}
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("config: cors.allowed_origins must not be empty"))
+	}
+
+	// Browsers refuse credentialed responses that allow any origin, so this combination never works.
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("config: cors.allow_credentials can't be used with the \"*\" origin"))
+	}
+
+	errs = append(errs, c.Selectors.validate()...)
+
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma separated list, trimming whitespace and dropping empty items.
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
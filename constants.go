@@ -1,25 +1,122 @@
 package main
 
+// API_VERSION is the current version of the API, which is served under "/api/{API_VERSION}" and aliased to "/api".
+const API_VERSION = "v1"
+
 const BASE_URL = "https://playoverwatch.com/en-us/career/"
 const SEARCH_URL = "https://playoverwatch.com/search/account-by-name/"
 const PATCH_NOTE_URL = "https://cache-eu.battle.net/system/cms/oauth/api/patchnote/list?program=pro&region=US&locale=enUS&type=RETAIL&page=1&pageSize=5&orderBy=buildNumber&buildNumberMin=0"
 
 const (
 	ERROR_NOT_FOUND = "HTTP 404. Not Found."
+	ERROR_INTERNAL  = "HTTP 500. Something went wrong while handling the request."
+	ERROR_NOT_READY = "HTTP 503. Not ready to handle requests yet."
+
+	ERROR_RATE_LIMITED = "HTTP 429. Too many requests, try again later."
+	ERROR_BAD_API_KEY  = "HTTP 401. Missing or invalid API key (X-API-Key header)."
+
+	ERROR_STORAGE_UNREACHABLE     = "HTTP 503. The storage path can't be written to."
+	ERROR_UPSTREAM_UNAVAILABLE    = "HTTP 503. The Overwatch site is failing, so requests to it are paused for a while."
+	ERROR_UPSTREAM_LAYOUT_CHANGED = "HTTP 502. The layout of the Overwatch site changed, so the player's career page couldn't be read."
 
 	ERROR_PLAYER_NOT_FOUND = "Could not find a user with that platform, region and username/BattleTag combination."
 	ERROR_BAD_PLATFORM     = "Invalid platform supplied. Must be one of the following: [pc, psn, xbl]."
 	ERROR_BAD_REGION       = "Invalid region supplied. Must be one of the following: [us, eu, cn, kr, global]."
 	ERROR_BAD_TAG          = "Invalid tag supplied."
 	ERROR_BAD_MODE         = "Invalid mode. Must be one of the following: [quickplay, competitive]."
+	ERROR_BAD_FORMAT       = "Invalid format. Must be one of the following: [json, csv, ndjson]."
+	ERROR_FORMAT_NOT_LIST  = "The csv and ndjson formats are only available for lists."
+	ERROR_BAD_GROUP        = "Invalid group. Must be one of the following: [section]."
+	ERROR_BAD_OUTPUT       = "Invalid output. Must be one of the following: [table, json]."
+	ERROR_HERO_NOT_FOUND   = "Could not find a hero with that name on the player's profile."
+	ERROR_BAD_HTML         = "Invalid HTML. Must be a saved career page."
+	ERROR_PARSE_TOO_LARGE  = "HTTP 413. The career page is too large."
+	ERROR_PARSE_INCOMPLETE = "HTTP 422. The career page is missing sections every career page has."
+
+	ERROR_BAD_GRAPHQL_REQUEST = "Invalid GraphQL request. Must be a JSON object with a query, and optionally variables and an operationName."
+	ERROR_GRAPHQL_TOO_LARGE   = "HTTP 413. The GraphQL request is too large."
 )
 
+// GROUP_SECTION groups stats by their section (see StatSection and groupBySection).
+const GROUP_SECTION = "section"
+
 // No sets in Go, at least natively. We can use maps to emulate set behavior as an alternative.
 var (
 	PLATFORMS = map[string]bool{"pc": true, "psn": true, "xbl": true}
 	REGIONS   = map[string]bool{"us": true, "eu": true, "cn": true, "kr": true, "global": true}
 	MODES     = map[string]bool{"quickplay": true, "competitive": true}
+	FORMATS   = map[string]bool{FORMAT_JSON: true, FORMAT_CSV: true, FORMAT_NDJSON: true}
+	GROUPS    = map[string]bool{GROUP_SECTION: true}
+
+	// OUTPUTS are the output formats of the command-line tool.
+	OUTPUTS = map[string]bool{"table": true, "json": true}
 
 	// TODO: Heros
 	HEROS = map[string]bool{}
 )
+
+// ALL_HEROES is the (lowercase) name of the "hero" holding the combined stats of every hero.
+const ALL_HEROES = "all heroes"
+
+// ALL_HEROES_CATEGORY_ID is the data-category-id of the combined stats of every hero.
+const ALL_HEROES_CATEGORY_ID = "0x02E00000FFFFFFFF"
+
+const (
+	ROLE_TANK    = "tank"
+	ROLE_DAMAGE  = "damage"
+	ROLE_SUPPORT = "support"
+)
+
+type skillTier struct {
+	Name           string
+	MinSkillRating int
+}
+
+// SKILL_TIERS are the competitive tiers, from lowest to highest, along with the lowest skill rating of each.
+var SKILL_TIERS = []skillTier{
+	{"Bronze", 1},
+	{"Silver", 1500},
+	{"Gold", 2000},
+	{"Platinum", 2500},
+	{"Diamond", 3000},
+	{"Master", 3500},
+	{"Grandmaster", 4000},
+}
+
+// ROLES is the on-screen order of the roles, used when aggregating stats by role.
+var ROLES = []string{ROLE_TANK, ROLE_DAMAGE, ROLE_SUPPORT}
+
+// HERO_ROLES maps each hero (lowercase, as found in the career page) to their role.
+var HERO_ROLES = map[string]string{
+	"d.va":          ROLE_TANK,
+	"orisa":         ROLE_TANK,
+	"reinhardt":     ROLE_TANK,
+	"roadhog":       ROLE_TANK,
+	"winston":       ROLE_TANK,
+	"wrecking ball": ROLE_TANK,
+	"zarya":         ROLE_TANK,
+
+	"ashe":        ROLE_DAMAGE,
+	"bastion":     ROLE_DAMAGE,
+	"doomfist":    ROLE_DAMAGE,
+	"genji":       ROLE_DAMAGE,
+	"hanzo":       ROLE_DAMAGE,
+	"junkrat":     ROLE_DAMAGE,
+	"mccree":      ROLE_DAMAGE,
+	"mei":         ROLE_DAMAGE,
+	"pharah":      ROLE_DAMAGE,
+	"reaper":      ROLE_DAMAGE,
+	"soldier: 76": ROLE_DAMAGE,
+	"sombra":      ROLE_DAMAGE,
+	"symmetra":    ROLE_DAMAGE,
+	"torbjörn":    ROLE_DAMAGE,
+	"tracer":      ROLE_DAMAGE,
+	"widowmaker":  ROLE_DAMAGE,
+
+	"ana":      ROLE_SUPPORT,
+	"brigitte": ROLE_SUPPORT,
+	"lúcio":    ROLE_SUPPORT,
+	"mercy":    ROLE_SUPPORT,
+	"moira":    ROLE_SUPPORT,
+	"zenyatta": ROLE_SUPPORT,
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"net/http"
+)
+
+type ModeExport struct {
+	AllHeroStats   []Stat                     `json:"all_hero_stats"`
+	HerosBreakdown map[string][]HeroBreakdown `json:"heros_breakdown"`
+	Heroes         map[string][]Stat          `json:"heroes"`
+}
+
+type Export struct {
+	Profile      Profile               `json:"profile"`
+	Achievements []Achievement         `json:"achievements"`
+	Modes        map[string]ModeExport `json:"modes"`
+}
+
+// ExportHandler retrieves everything known about the player (profile, achievements and the stats of every mode) and
+// returns it as a single JSON object.
+// The player's profile is only fetched once, which makes this suitable for archiving a player.
+func ExportHandler(w http.ResponseWriter, r *http.Request) {
+	// Get the platform, region and tag from the request URL.
+	// Pack these into a new Player for future use.
+	p := getPlayer(r)
+
+	// Call helper function to build the export.
+	export := GetExport(p.GetProfileDoc(), p, p.GetMatchingAccount())
+
+	// Call helper function to marshal the export to JSON.
+	MarshalAndHandleErrors(w, r, export)
+}
+
+// GetExport returns the player's profile, achievements and the stats of every mode in MODES, all parsed from the same
+// HTML document.
+// The layout of the document is checked for every parser first (see CheckLayout).
+func GetExport(d *goquery.Document, p *Player, matchingProfile Account) Export {
+	CheckLayout(p.context(), d, PARSER_PROFILE, "")
+	CheckLayout(p.context(), d, PARSER_ACHIEVEMENTS, "")
+	for mode := range MODES {
+		for _, parser := range []string{PARSER_ALL_HERO_STATS, PARSER_HEROS_BREAKDOWN, PARSER_HERO_STATS} {
+			CheckLayout(p.context(), d, parser, mode)
+		}
+	}
+
+	export := Export{
+		Profile:      GetProfile(d, p, matchingProfile),
+		Achievements: GetAchievements(d),
+		Modes:        map[string]ModeExport{},
+	}
+
+	for mode := range MODES {
+		export.Modes[mode] = ModeExport{
+			AllHeroStats:   GetAllHeroStats(d, mode),
+			HerosBreakdown: GetHeroBreakdowns(d, mode),
+			Heroes:         GetStatsByHero(d, mode),
+		}
+	}
+
+	return export
+}
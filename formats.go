@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	FORMAT_JSON   = "json"
+	FORMAT_CSV    = "csv"
+	FORMAT_NDJSON = "ndjson"
+)
+
+var errNotAList = errors.New("response is not a list")
+
+// MEDIA_TYPES are the formats of each media type the Accept header can ask for.
+var MEDIA_TYPES = map[string]string{
+	"application/json":     FORMAT_JSON,
+	"text/csv":             FORMAT_CSV,
+	"application/x-ndjson": FORMAT_NDJSON,
+}
+
+// getFormat returns the output format requested by the caller.
+// The "format" query parameter takes precedence over the Accept header. If neither asks for a known format, JSON is
+// used.
+func getFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+
+	return getAcceptedFormat(r.Header.Values("Accept"))
+}
+
+// getAcceptedFormat returns the format of the media type the Accept header(s) prefer, that is the known media type
+// with the highest quality ("q" parameter, 1 if missing), or the first of them on a tie. Media types with a quality
+// of 0 are refused. Returns JSON if no known media type is accepted (i.e. "*/*").
+func getAcceptedFormat(accept []string) string {
+	format, best := FORMAT_JSON, 0.0
+	for _, header := range accept {
+		for _, mediaRange := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+
+			f, ok := MEDIA_TYPES[mediaType]
+			if !ok {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+
+			if quality > best {
+				format, best = f, quality
+			}
+		}
+	}
+
+	return format
+}
+
+func formatIsValid(format string) bool {
+	return FORMATS[format]
+}
+
+// getGroup returns how the caller asked for stats to be grouped ("group" query parameter). Stats are returned as a flat
+// list if it is empty.
+func getGroup(r *http.Request) string {
+	return strings.ToLower(r.URL.Query().Get("group"))
+}
+
+func groupIsValid(group string) bool {
+	return group == "" || GROUPS[group]
+}
+
+// groupBySection returns true if the caller asked for stats grouped by section ("?group=section"), rather than a
+// flat list.
+func groupBySection(r *http.Request) bool {
+	return getGroup(r) == GROUP_SECTION
+}
+
+// record is a single row of a list response. The keys are kept in the order they appear in the JSON object, so CSV
+// columns match the order of the struct fields.
+type record struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// toRecords flattens a JSON encoded list response into records.
+// Lists of objects (i.e. []Stat) give one record per object. Maps of lists (i.e. map[string][]HeroBreakdown) give one
+// record per list item, with the map key added to the record as "key".
+// Any other response can't be represented as rows, and errNotAList is returned.
+func toRecords(response []byte) ([]record, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(response, &list); err == nil {
+		return listToRecords(list, "")
+	}
+
+	keys, values, err := decodeObject(response)
+	if err != nil {
+		return nil, errNotAList
+	}
+
+	records := []record{}
+	for _, k := range keys {
+		if err := json.Unmarshal(values[k], &list); err != nil {
+			return nil, errNotAList
+		}
+
+		keyed, err := listToRecords(list, k)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, keyed...)
+	}
+
+	return records, nil
+}
+
+// listToRecords converts each item of the list into a record. If key isn't empty, it is added as the first field of
+// each record.
+func listToRecords(list []json.RawMessage, key string) ([]record, error) {
+	records := []record{}
+	for _, item := range list {
+		keys, values, err := decodeObject(item)
+		if err != nil {
+			return nil, errNotAList
+		}
+
+		if key != "" {
+			encodedKey, _ := json.Marshal(key)
+			keys = append([]string{"key"}, keys...)
+			values["key"] = encodedKey
+		}
+
+		records = append(records, record{keys, values})
+	}
+
+	return records, nil
+}
+
+// decodeObject decodes a JSON object, returning its keys (in order) and their raw values.
+func decodeObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+
+	t, err := d.Token()
+	if err != nil || t != json.Delim('{') {
+		return nil, nil, errNotAList
+	}
+
+	keys := []string{}
+	values := map[string]json.RawMessage{}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var value json.RawMessage
+		if err := d.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		key := t.(string)
+		keys = append(keys, key)
+		values[key] = value
+	}
+
+	return keys, values, nil
+}
+
+// marshalCSV writes the records as CSV, with a header row made from the keys of every record (in the order they are
+// first seen).
+func marshalCSV(records []record) ([]byte, error) {
+	header := []string{}
+	seen := map[string]bool{}
+	for _, rec := range records {
+		for _, k := range rec.keys {
+			if !seen[k] {
+				seen[k] = true
+				header = append(header, k)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(header)
+
+	for _, rec := range records {
+		row := make([]string, len(header))
+		for i, k := range header {
+			row[i] = csvValue(rec.values[k])
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// csvValue returns the cell for a single JSON value. Strings are unquoted, missing values and nulls are left empty
+// and anything else (numbers, booleans, nested objects and lists) is written as-is.
+func csvValue(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+
+	return string(value)
+}
+
+// marshalNDJSON writes each record as a JSON object on its own line.
+func marshalNDJSON(records []record) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, rec := range records {
+		buf.WriteByte('{')
+		for i, k := range rec.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodedKey, _ := json.Marshal(k)
+			buf.Write(encodedKey)
+			buf.WriteByte(':')
+			buf.Write(rec.values[k])
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGetAcceptedFormat(t *testing.T) {
+	tests := []struct {
+		accept []string
+		want   string
+	}{
+		{nil, FORMAT_JSON},
+		{[]string{"*/*"}, FORMAT_JSON},
+		{[]string{"text/csv"}, FORMAT_CSV},
+		{[]string{"application/x-ndjson"}, FORMAT_NDJSON},
+		{[]string{"text/html, text/csv"}, FORMAT_CSV},
+		{[]string{"application/json;q=0.5, text/csv;q=0.9"}, FORMAT_CSV},
+		{[]string{"text/csv;q=0.5, application/json"}, FORMAT_JSON},
+		{[]string{"text/csv, application/x-ndjson"}, FORMAT_CSV},
+		{[]string{"text/csv;q=0"}, FORMAT_JSON},
+		{[]string{"text/csv;q=oops, application/x-ndjson;q=0.1"}, FORMAT_NDJSON},
+		{[]string{"text/csv;q=0.2", "application/x-ndjson;q=0.3"}, FORMAT_NDJSON},
+	}
+
+	for _, test := range tests {
+		if got := getAcceptedFormat(test.accept); got != test.want {
+			t.Errorf("%q: got %s, want %s", test.accept, got, test.want)
+		}
+	}
+}
+
+func TestToRecords(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		keys     [][]string
+		err      error
+	}{
+		{"list of objects", `[{"name":"a","value":"1"},{"name":"b","value":"2"}]`, [][]string{{"name", "value"}, {"name", "value"}}, nil},
+		{"empty list", `[]`, [][]string{}, nil},
+		{"map of lists", `{"x":[{"hero":"a"}],"y":[{"hero":"b"},{"hero":"c"}]}`, [][]string{{"key", "hero"}, {"key", "hero"}, {"key", "hero"}}, nil},
+		{"object", `{"username":"a","level":1}`, nil, errNotAList},
+		{"list of strings", `["a","b"]`, nil, errNotAList},
+		{"string", `"a"`, nil, errNotAList},
+	}
+
+	for _, test := range tests {
+		records, err := toRecords([]byte(test.response))
+		if err != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.err)
+			continue
+		}
+
+		keys := [][]string{}
+		for _, rec := range records {
+			keys = append(keys, rec.keys)
+		}
+
+		if test.err == nil && !slices.EqualFunc(keys, test.keys, slices.Equal) {
+			t.Errorf("%s: got keys %v, want %v", test.name, keys, test.keys)
+		}
+	}
+}
+
+func TestFormatMiddlewareVary(t *testing.T) {
+	handler := FormatMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if vary := recorder.Header().Values("Vary"); !slices.Contains(vary, "Accept") {
+		t.Errorf("got Vary %v, want Accept", vary)
+	}
+}
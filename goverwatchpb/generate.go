@@ -0,0 +1,4 @@
+// Package goverwatchpb contains the protobuf messages and gRPC service generated from goverwatch.proto.
+package goverwatchpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative goverwatch.proto
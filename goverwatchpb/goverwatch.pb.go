@@ -0,0 +1,1386 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: goverwatch.proto
+
+package goverwatchpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_goverwatch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type SearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_goverwatch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchResult) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *SearchResult) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *SearchResult) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_goverwatch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type PlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerRequest) Reset() {
+	*x = PlayerRequest{}
+	mi := &file_goverwatch_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerRequest) ProtoMessage() {}
+
+func (x *PlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerRequest.ProtoReflect.Descriptor instead.
+func (*PlayerRequest) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlayerRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *PlayerRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *PlayerRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type ModeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	Mode          string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModeRequest) Reset() {
+	*x = ModeRequest{}
+	mi := &file_goverwatch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeRequest) ProtoMessage() {}
+
+func (x *ModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeRequest.ProtoReflect.Descriptor instead.
+func (*ModeRequest) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ModeRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *ModeRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ModeRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ModeRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type HeroRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	Mode          string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	Hero          string                 `protobuf:"bytes,5,opt,name=hero,proto3" json:"hero,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeroRequest) Reset() {
+	*x = HeroRequest{}
+	mi := &file_goverwatch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeroRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeroRequest) ProtoMessage() {}
+
+func (x *HeroRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeroRequest.ProtoReflect.Descriptor instead.
+func (*HeroRequest) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HeroRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *HeroRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *HeroRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *HeroRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *HeroRequest) GetHero() string {
+	if x != nil {
+		return x.Hero
+	}
+	return ""
+}
+
+type Level struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Displayed     string                 `protobuf:"bytes,1,opt,name=displayed,proto3" json:"displayed,omitempty"`
+	Actual        int32                  `protobuf:"varint,2,opt,name=actual,proto3" json:"actual,omitempty"`
+	Stars         int32                  `protobuf:"varint,3,opt,name=stars,proto3" json:"stars,omitempty"`
+	Portrait      string                 `protobuf:"bytes,4,opt,name=portrait,proto3" json:"portrait,omitempty"`
+	Tier          string                 `protobuf:"bytes,5,opt,name=tier,proto3" json:"tier,omitempty"`
+	StarsPortrait string                 `protobuf:"bytes,6,opt,name=stars_portrait,json=starsPortrait,proto3" json:"stars_portrait,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Level) Reset() {
+	*x = Level{}
+	mi := &file_goverwatch_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Level) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Level) ProtoMessage() {}
+
+func (x *Level) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Level.ProtoReflect.Descriptor instead.
+func (*Level) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Level) GetDisplayed() string {
+	if x != nil {
+		return x.Displayed
+	}
+	return ""
+}
+
+func (x *Level) GetActual() int32 {
+	if x != nil {
+		return x.Actual
+	}
+	return 0
+}
+
+func (x *Level) GetStars() int32 {
+	if x != nil {
+		return x.Stars
+	}
+	return 0
+}
+
+func (x *Level) GetPortrait() string {
+	if x != nil {
+		return x.Portrait
+	}
+	return ""
+}
+
+func (x *Level) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *Level) GetStarsPortrait() string {
+	if x != nil {
+		return x.StarsPortrait
+	}
+	return ""
+}
+
+type Mode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Won           int32                  `protobuf:"varint,1,opt,name=won,proto3" json:"won,omitempty"`
+	Lost          int32                  `protobuf:"varint,2,opt,name=lost,proto3" json:"lost,omitempty"`
+	Played        int32                  `protobuf:"varint,3,opt,name=played,proto3" json:"played,omitempty"`
+	Time          string                 `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mode) Reset() {
+	*x = Mode{}
+	mi := &file_goverwatch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mode) ProtoMessage() {}
+
+func (x *Mode) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mode.ProtoReflect.Descriptor instead.
+func (*Mode) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Mode) GetWon() int32 {
+	if x != nil {
+		return x.Won
+	}
+	return 0
+}
+
+func (x *Mode) GetLost() int32 {
+	if x != nil {
+		return x.Lost
+	}
+	return 0
+}
+
+func (x *Mode) GetPlayed() int32 {
+	if x != nil {
+		return x.Played
+	}
+	return 0
+}
+
+func (x *Mode) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type Modes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quickplay     *Mode                  `protobuf:"bytes,1,opt,name=quickplay,proto3" json:"quickplay,omitempty"`
+	Competitive   *Mode                  `protobuf:"bytes,2,opt,name=competitive,proto3" json:"competitive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Modes) Reset() {
+	*x = Modes{}
+	mi := &file_goverwatch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Modes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Modes) ProtoMessage() {}
+
+func (x *Modes) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Modes.ProtoReflect.Descriptor instead.
+func (*Modes) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Modes) GetQuickplay() *Mode {
+	if x != nil {
+		return x.Quickplay
+	}
+	return nil
+}
+
+func (x *Modes) GetCompetitive() *Mode {
+	if x != nil {
+		return x.Competitive
+	}
+	return nil
+}
+
+type RoleRank struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	SkillRating   int32                  `protobuf:"varint,2,opt,name=skill_rating,json=skillRating,proto3" json:"skill_rating,omitempty"`
+	Tier          string                 `protobuf:"bytes,3,opt,name=tier,proto3" json:"tier,omitempty"`
+	RankImg       string                 `protobuf:"bytes,4,opt,name=rank_img,json=rankImg,proto3" json:"rank_img,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleRank) Reset() {
+	*x = RoleRank{}
+	mi := &file_goverwatch_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleRank) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleRank) ProtoMessage() {}
+
+func (x *RoleRank) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleRank.ProtoReflect.Descriptor instead.
+func (*RoleRank) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RoleRank) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *RoleRank) GetSkillRating() int32 {
+	if x != nil {
+		return x.SkillRating
+	}
+	return 0
+}
+
+func (x *RoleRank) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *RoleRank) GetRankImg() string {
+	if x != nil {
+		return x.RankImg
+	}
+	return ""
+}
+
+type CompetitiveRank struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          string                 `protobuf:"bytes,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	RankImg       string                 `protobuf:"bytes,2,opt,name=rank_img,json=rankImg,proto3" json:"rank_img,omitempty"`
+	SkillRating   int32                  `protobuf:"varint,3,opt,name=skill_rating,json=skillRating,proto3" json:"skill_rating,omitempty"`
+	Tier          string                 `protobuf:"bytes,4,opt,name=tier,proto3" json:"tier,omitempty"`
+	Roles         []*RoleRank            `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompetitiveRank) Reset() {
+	*x = CompetitiveRank{}
+	mi := &file_goverwatch_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompetitiveRank) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompetitiveRank) ProtoMessage() {}
+
+func (x *CompetitiveRank) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompetitiveRank.ProtoReflect.Descriptor instead.
+func (*CompetitiveRank) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CompetitiveRank) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+func (x *CompetitiveRank) GetRankImg() string {
+	if x != nil {
+		return x.RankImg
+	}
+	return ""
+}
+
+func (x *CompetitiveRank) GetSkillRating() int32 {
+	if x != nil {
+		return x.SkillRating
+	}
+	return 0
+}
+
+func (x *CompetitiveRank) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *CompetitiveRank) GetRoles() []*RoleRank {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type Profile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Avatar        string                 `protobuf:"bytes,2,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Level         *Level                 `protobuf:"bytes,3,opt,name=level,proto3" json:"level,omitempty"`
+	Modes         *Modes                 `protobuf:"bytes,4,opt,name=modes,proto3" json:"modes,omitempty"`
+	Competitive   *CompetitiveRank       `protobuf:"bytes,5,opt,name=competitive,proto3" json:"competitive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_goverwatch_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Profile) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Profile) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *Profile) GetLevel() *Level {
+	if x != nil {
+		return x.Level
+	}
+	return nil
+}
+
+func (x *Profile) GetModes() *Modes {
+	if x != nil {
+		return x.Modes
+	}
+	return nil
+}
+
+func (x *Profile) GetCompetitive() *CompetitiveRank {
+	if x != nil {
+		return x.Competitive
+	}
+	return nil
+}
+
+type Achievement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Finished      bool                   `protobuf:"varint,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Achievement) Reset() {
+	*x = Achievement{}
+	mi := &file_goverwatch_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Achievement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Achievement) ProtoMessage() {}
+
+func (x *Achievement) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Achievement.ProtoReflect.Descriptor instead.
+func (*Achievement) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Achievement) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Achievement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Achievement) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Achievement) GetFinished() bool {
+	if x != nil {
+		return x.Finished
+	}
+	return false
+}
+
+type AchievementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Achievements  []*Achievement         `protobuf:"bytes,1,rep,name=achievements,proto3" json:"achievements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AchievementsResponse) Reset() {
+	*x = AchievementsResponse{}
+	mi := &file_goverwatch_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AchievementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AchievementsResponse) ProtoMessage() {}
+
+func (x *AchievementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AchievementsResponse.ProtoReflect.Descriptor instead.
+func (*AchievementsResponse) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AchievementsResponse) GetAchievements() []*Achievement {
+	if x != nil {
+		return x.Achievements
+	}
+	return nil
+}
+
+type Stat struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value           string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	SectionName     string                 `protobuf:"bytes,3,opt,name=section_name,json=sectionName,proto3" json:"section_name,omitempty"`
+	StatKey         string                 `protobuf:"bytes,4,opt,name=stat_key,json=statKey,proto3" json:"stat_key,omitempty"`
+	StatKeyUnstable bool                   `protobuf:"varint,5,opt,name=stat_key_unstable,json=statKeyUnstable,proto3" json:"stat_key_unstable,omitempty"`
+	CanonicalName   string                 `protobuf:"bytes,6,opt,name=canonical_name,json=canonicalName,proto3" json:"canonical_name,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Stat) Reset() {
+	*x = Stat{}
+	mi := &file_goverwatch_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stat) ProtoMessage() {}
+
+func (x *Stat) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stat.ProtoReflect.Descriptor instead.
+func (*Stat) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Stat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stat) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Stat) GetSectionName() string {
+	if x != nil {
+		return x.SectionName
+	}
+	return ""
+}
+
+func (x *Stat) GetStatKey() string {
+	if x != nil {
+		return x.StatKey
+	}
+	return ""
+}
+
+func (x *Stat) GetStatKeyUnstable() bool {
+	if x != nil {
+		return x.StatKeyUnstable
+	}
+	return false
+}
+
+func (x *Stat) GetCanonicalName() string {
+	if x != nil {
+		return x.CanonicalName
+	}
+	return ""
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*Stat                `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	mi := &file_goverwatch_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StatsResponse) GetStats() []*Stat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type HeroBreakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hero          string                 `protobuf:"bytes,1,opt,name=hero,proto3" json:"hero,omitempty"`
+	Image         string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Percentage    float64                `protobuf:"fixed64,4,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeroBreakdown) Reset() {
+	*x = HeroBreakdown{}
+	mi := &file_goverwatch_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeroBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeroBreakdown) ProtoMessage() {}
+
+func (x *HeroBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeroBreakdown.ProtoReflect.Descriptor instead.
+func (*HeroBreakdown) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HeroBreakdown) GetHero() string {
+	if x != nil {
+		return x.Hero
+	}
+	return ""
+}
+
+func (x *HeroBreakdown) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *HeroBreakdown) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *HeroBreakdown) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+type HeroBreakdownList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Heroes        []*HeroBreakdown       `protobuf:"bytes,1,rep,name=heroes,proto3" json:"heroes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeroBreakdownList) Reset() {
+	*x = HeroBreakdownList{}
+	mi := &file_goverwatch_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeroBreakdownList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeroBreakdownList) ProtoMessage() {}
+
+func (x *HeroBreakdownList) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeroBreakdownList.ProtoReflect.Descriptor instead.
+func (*HeroBreakdownList) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HeroBreakdownList) GetHeroes() []*HeroBreakdown {
+	if x != nil {
+		return x.Heroes
+	}
+	return nil
+}
+
+type HeroBreakdownResponse struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Stats         map[string]*HeroBreakdownList `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeroBreakdownResponse) Reset() {
+	*x = HeroBreakdownResponse{}
+	mi := &file_goverwatch_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeroBreakdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeroBreakdownResponse) ProtoMessage() {}
+
+func (x *HeroBreakdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goverwatch_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeroBreakdownResponse.ProtoReflect.Descriptor instead.
+func (*HeroBreakdownResponse) Descriptor() ([]byte, []int) {
+	return file_goverwatch_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HeroBreakdownResponse) GetStats() map[string]*HeroBreakdownList {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+var File_goverwatch_proto protoreflect.FileDescriptor
+
+const file_goverwatch_proto_rawDesc = "" +
+	"\n" +
+	"\x10goverwatch.proto\x12\rgoverwatch.v1\"!\n" +
+	"\rSearchRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"T\n" +
+	"\fSearchResult\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\"G\n" +
+	"\x0eSearchResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.goverwatch.v1.SearchResultR\aresults\"U\n" +
+	"\rPlayerRequest\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\"g\n" +
+	"\vModeRequest\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\tR\x04mode\"{\n" +
+	"\vHeroRequest\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\tR\x04mode\x12\x12\n" +
+	"\x04hero\x18\x05 \x01(\tR\x04hero\"\xaa\x01\n" +
+	"\x05Level\x12\x1c\n" +
+	"\tdisplayed\x18\x01 \x01(\tR\tdisplayed\x12\x16\n" +
+	"\x06actual\x18\x02 \x01(\x05R\x06actual\x12\x14\n" +
+	"\x05stars\x18\x03 \x01(\x05R\x05stars\x12\x1a\n" +
+	"\bportrait\x18\x04 \x01(\tR\bportrait\x12\x12\n" +
+	"\x04tier\x18\x05 \x01(\tR\x04tier\x12%\n" +
+	"\x0estars_portrait\x18\x06 \x01(\tR\rstarsPortrait\"X\n" +
+	"\x04Mode\x12\x10\n" +
+	"\x03won\x18\x01 \x01(\x05R\x03won\x12\x12\n" +
+	"\x04lost\x18\x02 \x01(\x05R\x04lost\x12\x16\n" +
+	"\x06played\x18\x03 \x01(\x05R\x06played\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\tR\x04time\"q\n" +
+	"\x05Modes\x121\n" +
+	"\tquickplay\x18\x01 \x01(\v2\x13.goverwatch.v1.ModeR\tquickplay\x125\n" +
+	"\vcompetitive\x18\x02 \x01(\v2\x13.goverwatch.v1.ModeR\vcompetitive\"p\n" +
+	"\bRoleRank\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12!\n" +
+	"\fskill_rating\x18\x02 \x01(\x05R\vskillRating\x12\x12\n" +
+	"\x04tier\x18\x03 \x01(\tR\x04tier\x12\x19\n" +
+	"\brank_img\x18\x04 \x01(\tR\arankImg\"\xa6\x01\n" +
+	"\x0fCompetitiveRank\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\tR\x04rank\x12\x19\n" +
+	"\brank_img\x18\x02 \x01(\tR\arankImg\x12!\n" +
+	"\fskill_rating\x18\x03 \x01(\x05R\vskillRating\x12\x12\n" +
+	"\x04tier\x18\x04 \x01(\tR\x04tier\x12-\n" +
+	"\x05roles\x18\x05 \x03(\v2\x17.goverwatch.v1.RoleRankR\x05roles\"\xd7\x01\n" +
+	"\aProfile\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x16\n" +
+	"\x06avatar\x18\x02 \x01(\tR\x06avatar\x12*\n" +
+	"\x05level\x18\x03 \x01(\v2\x14.goverwatch.v1.LevelR\x05level\x12*\n" +
+	"\x05modes\x18\x04 \x01(\v2\x14.goverwatch.v1.ModesR\x05modes\x12@\n" +
+	"\vcompetitive\x18\x05 \x01(\v2\x1e.goverwatch.v1.CompetitiveRankR\vcompetitive\"~\n" +
+	"\vAchievement\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x03 \x01(\tR\bimageUrl\x12\x1a\n" +
+	"\bfinished\x18\x04 \x01(\bR\bfinished\"V\n" +
+	"\x14AchievementsResponse\x12>\n" +
+	"\fachievements\x18\x01 \x03(\v2\x1a.goverwatch.v1.AchievementR\fachievements\"\xc1\x01\n" +
+	"\x04Stat\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12!\n" +
+	"\fsection_name\x18\x03 \x01(\tR\vsectionName\x12\x19\n" +
+	"\bstat_key\x18\x04 \x01(\tR\astatKey\x12*\n" +
+	"\x11stat_key_unstable\x18\x05 \x01(\bR\x0fstatKeyUnstable\x12%\n" +
+	"\x0ecanonical_name\x18\x06 \x01(\tR\rcanonicalName\":\n" +
+	"\rStatsResponse\x12)\n" +
+	"\x05stats\x18\x01 \x03(\v2\x13.goverwatch.v1.StatR\x05stats\"o\n" +
+	"\rHeroBreakdown\x12\x12\n" +
+	"\x04hero\x18\x01 \x01(\tR\x04hero\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x04 \x01(\x01R\n" +
+	"percentage\"I\n" +
+	"\x11HeroBreakdownList\x124\n" +
+	"\x06heroes\x18\x01 \x03(\v2\x1c.goverwatch.v1.HeroBreakdownR\x06heroes\"\xba\x01\n" +
+	"\x15HeroBreakdownResponse\x12E\n" +
+	"\x05stats\x18\x01 \x03(\v2/.goverwatch.v1.HeroBreakdownResponse.StatsEntryR\x05stats\x1aZ\n" +
+	"\n" +
+	"StatsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x126\n" +
+	"\x05value\x18\x02 \x01(\v2 .goverwatch.v1.HeroBreakdownListR\x05value:\x028\x012\xda\x03\n" +
+	"\n" +
+	"Goverwatch\x12E\n" +
+	"\x06Search\x12\x1c.goverwatch.v1.SearchRequest\x1a\x1d.goverwatch.v1.SearchResponse\x12B\n" +
+	"\n" +
+	"GetProfile\x12\x1c.goverwatch.v1.PlayerRequest\x1a\x16.goverwatch.v1.Profile\x12T\n" +
+	"\x0fGetAchievements\x12\x1c.goverwatch.v1.PlayerRequest\x1a#.goverwatch.v1.AchievementsResponse\x12K\n" +
+	"\x0fGetAllHeroStats\x12\x1a.goverwatch.v1.ModeRequest\x1a\x1c.goverwatch.v1.StatsResponse\x12T\n" +
+	"\x10GetHeroBreakdown\x12\x1a.goverwatch.v1.ModeRequest\x1a$.goverwatch.v1.HeroBreakdownResponse\x12H\n" +
+	"\fGetHeroStats\x12\x1a.goverwatch.v1.HeroRequest\x1a\x1c.goverwatch.v1.StatsResponseB0Z.github.com/KyleCrowley/goverwatch/goverwatchpbb\x06proto3"
+
+var (
+	file_goverwatch_proto_rawDescOnce sync.Once
+	file_goverwatch_proto_rawDescData []byte
+)
+
+func file_goverwatch_proto_rawDescGZIP() []byte {
+	file_goverwatch_proto_rawDescOnce.Do(func() {
+		file_goverwatch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goverwatch_proto_rawDesc), len(file_goverwatch_proto_rawDesc)))
+	})
+	return file_goverwatch_proto_rawDescData
+}
+
+var file_goverwatch_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_goverwatch_proto_goTypes = []any{
+	(*SearchRequest)(nil),         // 0: goverwatch.v1.SearchRequest
+	(*SearchResult)(nil),          // 1: goverwatch.v1.SearchResult
+	(*SearchResponse)(nil),        // 2: goverwatch.v1.SearchResponse
+	(*PlayerRequest)(nil),         // 3: goverwatch.v1.PlayerRequest
+	(*ModeRequest)(nil),           // 4: goverwatch.v1.ModeRequest
+	(*HeroRequest)(nil),           // 5: goverwatch.v1.HeroRequest
+	(*Level)(nil),                 // 6: goverwatch.v1.Level
+	(*Mode)(nil),                  // 7: goverwatch.v1.Mode
+	(*Modes)(nil),                 // 8: goverwatch.v1.Modes
+	(*RoleRank)(nil),              // 9: goverwatch.v1.RoleRank
+	(*CompetitiveRank)(nil),       // 10: goverwatch.v1.CompetitiveRank
+	(*Profile)(nil),               // 11: goverwatch.v1.Profile
+	(*Achievement)(nil),           // 12: goverwatch.v1.Achievement
+	(*AchievementsResponse)(nil),  // 13: goverwatch.v1.AchievementsResponse
+	(*Stat)(nil),                  // 14: goverwatch.v1.Stat
+	(*StatsResponse)(nil),         // 15: goverwatch.v1.StatsResponse
+	(*HeroBreakdown)(nil),         // 16: goverwatch.v1.HeroBreakdown
+	(*HeroBreakdownList)(nil),     // 17: goverwatch.v1.HeroBreakdownList
+	(*HeroBreakdownResponse)(nil), // 18: goverwatch.v1.HeroBreakdownResponse
+	nil,                           // 19: goverwatch.v1.HeroBreakdownResponse.StatsEntry
+}
+var file_goverwatch_proto_depIdxs = []int32{
+	1,  // 0: goverwatch.v1.SearchResponse.results:type_name -> goverwatch.v1.SearchResult
+	7,  // 1: goverwatch.v1.Modes.quickplay:type_name -> goverwatch.v1.Mode
+	7,  // 2: goverwatch.v1.Modes.competitive:type_name -> goverwatch.v1.Mode
+	9,  // 3: goverwatch.v1.CompetitiveRank.roles:type_name -> goverwatch.v1.RoleRank
+	6,  // 4: goverwatch.v1.Profile.level:type_name -> goverwatch.v1.Level
+	8,  // 5: goverwatch.v1.Profile.modes:type_name -> goverwatch.v1.Modes
+	10, // 6: goverwatch.v1.Profile.competitive:type_name -> goverwatch.v1.CompetitiveRank
+	12, // 7: goverwatch.v1.AchievementsResponse.achievements:type_name -> goverwatch.v1.Achievement
+	14, // 8: goverwatch.v1.StatsResponse.stats:type_name -> goverwatch.v1.Stat
+	16, // 9: goverwatch.v1.HeroBreakdownList.heroes:type_name -> goverwatch.v1.HeroBreakdown
+	19, // 10: goverwatch.v1.HeroBreakdownResponse.stats:type_name -> goverwatch.v1.HeroBreakdownResponse.StatsEntry
+	17, // 11: goverwatch.v1.HeroBreakdownResponse.StatsEntry.value:type_name -> goverwatch.v1.HeroBreakdownList
+	0,  // 12: goverwatch.v1.Goverwatch.Search:input_type -> goverwatch.v1.SearchRequest
+	3,  // 13: goverwatch.v1.Goverwatch.GetProfile:input_type -> goverwatch.v1.PlayerRequest
+	3,  // 14: goverwatch.v1.Goverwatch.GetAchievements:input_type -> goverwatch.v1.PlayerRequest
+	4,  // 15: goverwatch.v1.Goverwatch.GetAllHeroStats:input_type -> goverwatch.v1.ModeRequest
+	4,  // 16: goverwatch.v1.Goverwatch.GetHeroBreakdown:input_type -> goverwatch.v1.ModeRequest
+	5,  // 17: goverwatch.v1.Goverwatch.GetHeroStats:input_type -> goverwatch.v1.HeroRequest
+	2,  // 18: goverwatch.v1.Goverwatch.Search:output_type -> goverwatch.v1.SearchResponse
+	11, // 19: goverwatch.v1.Goverwatch.GetProfile:output_type -> goverwatch.v1.Profile
+	13, // 20: goverwatch.v1.Goverwatch.GetAchievements:output_type -> goverwatch.v1.AchievementsResponse
+	15, // 21: goverwatch.v1.Goverwatch.GetAllHeroStats:output_type -> goverwatch.v1.StatsResponse
+	18, // 22: goverwatch.v1.Goverwatch.GetHeroBreakdown:output_type -> goverwatch.v1.HeroBreakdownResponse
+	15, // 23: goverwatch.v1.Goverwatch.GetHeroStats:output_type -> goverwatch.v1.StatsResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_goverwatch_proto_init() }
+func file_goverwatch_proto_init() {
+	if File_goverwatch_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goverwatch_proto_rawDesc), len(file_goverwatch_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_goverwatch_proto_goTypes,
+		DependencyIndexes: file_goverwatch_proto_depIdxs,
+		MessageInfos:      file_goverwatch_proto_msgTypes,
+	}.Build()
+	File_goverwatch_proto = out.File
+	file_goverwatch_proto_goTypes = nil
+	file_goverwatch_proto_depIdxs = nil
+}
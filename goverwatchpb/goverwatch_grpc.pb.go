@@ -0,0 +1,311 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: goverwatch.proto
+
+package goverwatchpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Goverwatch_Search_FullMethodName           = "/goverwatch.v1.Goverwatch/Search"
+	Goverwatch_GetProfile_FullMethodName       = "/goverwatch.v1.Goverwatch/GetProfile"
+	Goverwatch_GetAchievements_FullMethodName  = "/goverwatch.v1.Goverwatch/GetAchievements"
+	Goverwatch_GetAllHeroStats_FullMethodName  = "/goverwatch.v1.Goverwatch/GetAllHeroStats"
+	Goverwatch_GetHeroBreakdown_FullMethodName = "/goverwatch.v1.Goverwatch/GetHeroBreakdown"
+	Goverwatch_GetHeroStats_FullMethodName     = "/goverwatch.v1.Goverwatch/GetHeroStats"
+)
+
+// GoverwatchClient is the client API for Goverwatch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GoverwatchClient interface {
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	GetProfile(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Profile, error)
+	GetAchievements(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*AchievementsResponse, error)
+	GetAllHeroStats(ctx context.Context, in *ModeRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	GetHeroBreakdown(ctx context.Context, in *ModeRequest, opts ...grpc.CallOption) (*HeroBreakdownResponse, error)
+	GetHeroStats(ctx context.Context, in *HeroRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+}
+
+type goverwatchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGoverwatchClient(cc grpc.ClientConnInterface) GoverwatchClient {
+	return &goverwatchClient{cc}
+}
+
+func (c *goverwatchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, Goverwatch_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goverwatchClient) GetProfile(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*Profile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, Goverwatch_GetProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goverwatchClient) GetAchievements(ctx context.Context, in *PlayerRequest, opts ...grpc.CallOption) (*AchievementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AchievementsResponse)
+	err := c.cc.Invoke(ctx, Goverwatch_GetAchievements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goverwatchClient) GetAllHeroStats(ctx context.Context, in *ModeRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, Goverwatch_GetAllHeroStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goverwatchClient) GetHeroBreakdown(ctx context.Context, in *ModeRequest, opts ...grpc.CallOption) (*HeroBreakdownResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeroBreakdownResponse)
+	err := c.cc.Invoke(ctx, Goverwatch_GetHeroBreakdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goverwatchClient) GetHeroStats(ctx context.Context, in *HeroRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, Goverwatch_GetHeroStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GoverwatchServer is the server API for Goverwatch service.
+// All implementations must embed UnimplementedGoverwatchServer
+// for forward compatibility.
+type GoverwatchServer interface {
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	GetProfile(context.Context, *PlayerRequest) (*Profile, error)
+	GetAchievements(context.Context, *PlayerRequest) (*AchievementsResponse, error)
+	GetAllHeroStats(context.Context, *ModeRequest) (*StatsResponse, error)
+	GetHeroBreakdown(context.Context, *ModeRequest) (*HeroBreakdownResponse, error)
+	GetHeroStats(context.Context, *HeroRequest) (*StatsResponse, error)
+	mustEmbedUnimplementedGoverwatchServer()
+}
+
+// UnimplementedGoverwatchServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGoverwatchServer struct{}
+
+func (UnimplementedGoverwatchServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedGoverwatchServer) GetProfile(context.Context, *PlayerRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (UnimplementedGoverwatchServer) GetAchievements(context.Context, *PlayerRequest) (*AchievementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAchievements not implemented")
+}
+func (UnimplementedGoverwatchServer) GetAllHeroStats(context.Context, *ModeRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllHeroStats not implemented")
+}
+func (UnimplementedGoverwatchServer) GetHeroBreakdown(context.Context, *ModeRequest) (*HeroBreakdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeroBreakdown not implemented")
+}
+func (UnimplementedGoverwatchServer) GetHeroStats(context.Context, *HeroRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeroStats not implemented")
+}
+func (UnimplementedGoverwatchServer) mustEmbedUnimplementedGoverwatchServer() {}
+func (UnimplementedGoverwatchServer) testEmbeddedByValue()                    {}
+
+// UnsafeGoverwatchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoverwatchServer will
+// result in compilation errors.
+type UnsafeGoverwatchServer interface {
+	mustEmbedUnimplementedGoverwatchServer()
+}
+
+func RegisterGoverwatchServer(s grpc.ServiceRegistrar, srv GoverwatchServer) {
+	// If the following call pancis, it indicates UnimplementedGoverwatchServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Goverwatch_ServiceDesc, srv)
+}
+
+func _Goverwatch_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goverwatch_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_GetProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).GetProfile(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goverwatch_GetAchievements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).GetAchievements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_GetAchievements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).GetAchievements(ctx, req.(*PlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goverwatch_GetAllHeroStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).GetAllHeroStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_GetAllHeroStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).GetAllHeroStats(ctx, req.(*ModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goverwatch_GetHeroBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).GetHeroBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_GetHeroBreakdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).GetHeroBreakdown(ctx, req.(*ModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Goverwatch_GetHeroStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoverwatchServer).GetHeroStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Goverwatch_GetHeroStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoverwatchServer).GetHeroStats(ctx, req.(*HeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Goverwatch_ServiceDesc is the grpc.ServiceDesc for Goverwatch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Goverwatch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goverwatch.v1.Goverwatch",
+	HandlerType: (*GoverwatchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _Goverwatch_Search_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _Goverwatch_GetProfile_Handler,
+		},
+		{
+			MethodName: "GetAchievements",
+			Handler:    _Goverwatch_GetAchievements_Handler,
+		},
+		{
+			MethodName: "GetAllHeroStats",
+			Handler:    _Goverwatch_GetAllHeroStats_Handler,
+		},
+		{
+			MethodName: "GetHeroBreakdown",
+			Handler:    _Goverwatch_GetHeroBreakdown_Handler,
+		},
+		{
+			MethodName: "GetHeroStats",
+			Handler:    _Goverwatch_GetHeroStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "goverwatch.proto",
+}
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/graphql-go/graphql"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MAX_GRAPHQL_SIZE is the largest GraphQL request body GraphQLHandler accepts. Queries are usually a few KB at most.
+const MAX_GRAPHQL_SIZE = 1 << 20
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type heroStats struct {
+	Name     string        `json:"name"`
+	Stats    []Stat        `json:"stats"`
+	Sections []StatSection `json:"sections"`
+}
+
+// newHeroStats returns the hero's stats, both as a flat list and grouped by section.
+func newHeroStats(name string, sections []StatSection) heroStats {
+	return heroStats{name, flattenStatSections(sections), sections}
+}
+
+type statBreakdown struct {
+	Stat   string          `json:"stat"`
+	Heroes []HeroBreakdown `json:"heroes"`
+}
+
+type namedTotal struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// docCache holds the HTML document (and matching account) of each player requested during a single GraphQL query, so
+// every field of the same player is resolved from one fetch.
+type docCache struct {
+	mutex    sync.Mutex
+	docs     map[string]*goquery.Document
+	accounts map[string]Account
+}
+
+type docCacheKey struct{}
+
+func (p *Player) cacheKey() string {
+	return p.Platform + "/" + p.Region + "/" + p.sanitizeBattleTag()
+}
+
+// getCache returns the docCache of the query the context belongs to.
+func getCache(ctx context.Context) *docCache {
+	return ctx.Value(docCacheKey{}).(*docCache)
+}
+
+// doc returns the player's HTML document, only fetching it the first time it is asked for.
+func (c *docCache) doc(p *Player) *goquery.Document {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	d, ok := c.docs[p.cacheKey()]
+	if !ok {
+		d = p.GetProfileDoc()
+		c.docs[p.cacheKey()] = d
+	}
+	return d
+}
+
+// account returns the account matching the player, or an error if the player doesn't exist.
+// The account is only searched for the first time it is asked for.
+func (c *docCache) account(p *Player) (Account, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if account, ok := c.accounts[p.cacheKey()]; ok {
+		return account, nil
+	}
+
+	accounts := p.GetAccountByName()
+	if len(accounts) == 0 {
+		return Account{}, errors.New(ERROR_PLAYER_NOT_FOUND)
+	}
+
+	account := p.FindMatchingAccount(accounts)
+	c.accounts[p.cacheKey()] = account
+	return account, nil
+}
+
+// modeArg returns the (validated) mode argument of the field.
+func modeArg(params graphql.ResolveParams) (string, error) {
+	mode := strings.ToLower(params.Args["mode"].(string))
+	if !modeIsValid(mode) {
+		return "", errors.New(ERROR_BAD_MODE)
+	}
+	return mode, nil
+}
+
+var modeArgs = graphql.FieldConfigArgument{
+	"mode": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+}
+
+var graphQLSchema = newGraphQLSchema()
+
+// newGraphQLSchema returns the GraphQL schema over player data.
+// Field names follow the JSON field names of the REST API, so the default resolvers can read them from the structs.
+func newGraphQLSchema() graphql.Schema {
+	statType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stat",
+		Fields: graphql.Fields{
+			"name":              &graphql.Field{Type: graphql.String},
+			"value":             &graphql.Field{Type: graphql.String},
+			"section_name":      &graphql.Field{Type: graphql.String},
+			"canonical_name":    &graphql.Field{Type: graphql.String},
+			"stat_key":          &graphql.Field{Type: graphql.String},
+			"stat_key_unstable": &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	heroBreakdownType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HeroBreakdown",
+		Fields: graphql.Fields{
+			"hero":       &graphql.Field{Type: graphql.String},
+			"image":      &graphql.Field{Type: graphql.String},
+			"value":      &graphql.Field{Type: graphql.String},
+			"percentage": &graphql.Field{Type: graphql.Float},
+		},
+	})
+
+	statBreakdownType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StatBreakdown",
+		Fields: graphql.Fields{
+			"stat":   &graphql.Field{Type: graphql.String},
+			"heroes": &graphql.Field{Type: graphql.NewList(heroBreakdownType)},
+		},
+	})
+
+	statSectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StatSection",
+		Fields: graphql.Fields{
+			"name":  &graphql.Field{Type: graphql.String},
+			"stats": &graphql.Field{Type: graphql.NewList(statType)},
+		},
+	})
+
+	heroType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Hero",
+		Fields: graphql.Fields{
+			"name":     &graphql.Field{Type: graphql.String},
+			"stats":    &graphql.Field{Type: graphql.NewList(statType)},
+			"sections": &graphql.Field{Type: graphql.NewList(statSectionType)},
+		},
+	})
+
+	achievementType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Achievement",
+		Fields: graphql.Fields{
+			"title":       &graphql.Field{Type: graphql.String},
+			"description": &graphql.Field{Type: graphql.String},
+			"image_url":   &graphql.Field{Type: graphql.String},
+			"finished":    &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	levelType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Level",
+		Fields: graphql.Fields{
+			"displayed":      &graphql.Field{Type: graphql.String},
+			"actual":         &graphql.Field{Type: graphql.Int},
+			"stars":          &graphql.Field{Type: graphql.Int},
+			"tier":           &graphql.Field{Type: graphql.String},
+			"portrait":       &graphql.Field{Type: graphql.String},
+			"stars_portrait": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	modeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mode",
+		Fields: graphql.Fields{
+			"won":    &graphql.Field{Type: graphql.Int},
+			"lost":   &graphql.Field{Type: graphql.Int},
+			"played": &graphql.Field{Type: graphql.Int},
+			"time":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	modesType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Modes",
+		Fields: graphql.Fields{
+			"quickplay":   &graphql.Field{Type: modeType},
+			"competitive": &graphql.Field{Type: modeType},
+		},
+	})
+
+	roleRankType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "RoleRank",
+		Fields: graphql.Fields{
+			"role":         &graphql.Field{Type: graphql.String},
+			"skill_rating": &graphql.Field{Type: graphql.Int},
+			"tier":         &graphql.Field{Type: graphql.String},
+			"rank_img":     &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	competitiveRankType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CompetitiveRank",
+		Fields: graphql.Fields{
+			"rank":         &graphql.Field{Type: graphql.String},
+			"rank_img":     &graphql.Field{Type: graphql.String},
+			"skill_rating": &graphql.Field{Type: graphql.Int},
+			"tier":         &graphql.Field{Type: graphql.String},
+			"roles":        &graphql.Field{Type: graphql.NewList(roleRankType)},
+		},
+	})
+
+	profileType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Profile",
+		Fields: graphql.Fields{
+			"username":    &graphql.Field{Type: graphql.String},
+			"avatar":      &graphql.Field{Type: graphql.String},
+			"level":       &graphql.Field{Type: levelType},
+			"modes":       &graphql.Field{Type: modesType},
+			"competitive": &graphql.Field{Type: competitiveRankType},
+		},
+	})
+
+	namedTotalType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "NamedTotal",
+		Fields: graphql.Fields{
+			"name":  &graphql.Field{Type: graphql.String},
+			"value": &graphql.Field{Type: graphql.Float},
+		},
+	})
+
+	roleSummaryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "RoleSummary",
+		Fields: graphql.Fields{
+			"role":              &graphql.Field{Type: graphql.String},
+			"heroes":            &graphql.Field{Type: graphql.NewList(graphql.String)},
+			"time_played":       &graphql.Field{Type: graphql.Float},
+			"time_played_share": &graphql.Field{Type: graphql.Float},
+			"games_won":         &graphql.Field{Type: graphql.Int},
+			"games_played":      &graphql.Field{Type: graphql.Int},
+			"win_rate":          &graphql.Field{Type: graphql.Float},
+			"combat": &graphql.Field{
+				Type: graphql.NewList(namedTotalType),
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					combat := params.Source.(RoleSummary).Combat
+
+					// The totals are keyed by stat key (see GetRoleSummaries), which is the name listed.
+					totals := []namedTotal{}
+					for name, value := range combat {
+						totals = append(totals, namedTotal{name, value})
+					}
+					sort.Slice(totals, func(i, j int) bool { return totals[i].Name < totals[j].Name })
+					return totals, nil
+				},
+			},
+		},
+	})
+
+	searchResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"platform": &graphql.Field{Type: graphql.String},
+			"region":   &graphql.Field{Type: graphql.String},
+			"tag":      &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	playerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Player",
+		Fields: graphql.Fields{
+			"platform": &graphql.Field{Type: graphql.String},
+			"region":   &graphql.Field{Type: graphql.String},
+			"tag":      &graphql.Field{Type: graphql.String},
+			"profile": &graphql.Field{
+				Type: profileType,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					p := params.Source.(*Player)
+					cache := getCache(params.Context)
+
+					account, err := cache.account(p)
+					if err != nil {
+						return nil, err
+					}
+
+					d := cache.doc(p)
+					CheckLayout(params.Context, d, PARSER_PROFILE, "")
+					return GetProfile(d, p, account), nil
+				},
+			},
+			"achievements": &graphql.Field{
+				Type: graphql.NewList(achievementType),
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_ACHIEVEMENTS, "")
+					return GetAchievements(d), nil
+				},
+			},
+			"all_hero_stats": &graphql.Field{
+				Type: graphql.NewList(statType),
+				Args: modeArgs,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_ALL_HERO_STATS, mode)
+					return GetAllHeroStats(d, mode), nil
+				},
+			},
+			"all_hero_stat_sections": &graphql.Field{
+				Type: graphql.NewList(statSectionType),
+				Args: modeArgs,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_ALL_HERO_STATS, mode)
+					return GetStatSections(d, mode, ALL_HEROES_CATEGORY_ID), nil
+				},
+			},
+			"heros_breakdown": &graphql.Field{
+				Type: graphql.NewList(statBreakdownType),
+				Args: modeArgs,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_HEROS_BREAKDOWN, mode)
+					breakdowns := GetHeroBreakdowns(d, mode)
+
+					stats := []statBreakdown{}
+					for stat, heroes := range breakdowns {
+						stats = append(stats, statBreakdown{stat, heroes})
+					}
+					sort.Slice(stats, func(i, j int) bool { return stats[i].Stat < stats[j].Stat })
+					return stats, nil
+				},
+			},
+			"hero": &graphql.Field{
+				Type: heroType,
+				Args: graphql.FieldConfigArgument{
+					"mode": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_HERO_STATS, mode)
+					name := strings.ToLower(params.Args["name"].(string))
+
+					hex, ok := GetHeroHexMap(d)[name]
+					if !ok {
+						return nil, nil
+					}
+					return newHeroStats(name, GetStatSections(d, mode, hex)), nil
+				},
+			},
+			"heroes": &graphql.Field{
+				Type: graphql.NewList(heroType),
+				Args: modeArgs,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_HERO_STATS, mode)
+
+					heroes := []heroStats{}
+					for name, sections := range GetStatSectionsByHero(d, mode) {
+						heroes = append(heroes, newHeroStats(name, sections))
+					}
+					sort.Slice(heroes, func(i, j int) bool { return heroes[i].Name < heroes[j].Name })
+					return heroes, nil
+				},
+			},
+			"roles": &graphql.Field{
+				Type: graphql.NewList(roleSummaryType),
+				Args: modeArgs,
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					mode, err := modeArg(params)
+					if err != nil {
+						return nil, err
+					}
+
+					p := params.Source.(*Player)
+					d := getCache(params.Context).doc(p)
+					CheckLayout(params.Context, d, PARSER_HEROS_BREAKDOWN, mode)
+					CheckLayout(params.Context, d, PARSER_HERO_STATS, mode)
+					return GetRoleSummaries(d, mode), nil
+				},
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"player": &graphql.Field{
+				Type: playerType,
+				Args: graphql.FieldConfigArgument{
+					"platform": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"region":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"tag":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					p := &Player{
+						Platform: strings.ToLower(params.Args["platform"].(string)),
+						Region:   strings.ToLower(params.Args["region"].(string)),
+						Tag:      params.Args["tag"].(string),
+						ctx:      params.Context,
+					}
+
+					if !p.platformIsValid() {
+						return nil, errors.New(ERROR_BAD_PLATFORM)
+					}
+
+					if !p.regionIsValid() {
+						return nil, errors.New(ERROR_BAD_REGION)
+					}
+
+					// Make sure the player exists before resolving any of their fields.
+					if _, err := getCache(params.Context).account(p); err != nil {
+						return nil, err
+					}
+
+					return p, nil
+				},
+			},
+			"search": &graphql.Field{
+				Type: graphql.NewList(searchResultType),
+				Args: graphql.FieldConfigArgument{
+					"tag": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+					p := Player{Tag: params.Args["tag"].(string), ctx: params.Context}
+					return GetSearchResults(p.GetAccountByName()), nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// GraphQLHandler executes a GraphQL query over player data, sent either as a JSON body (POST, at most
+// MAX_GRAPHQL_SIZE) or in the "query", "variables" and "operationName" query parameters (GET).
+// Each player is only fetched once per query, no matter how many of their fields are asked for.
+func GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_GRAPHQL_SIZE)).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				ReturnErrorResponse(w, r, http.StatusRequestEntityTooLarge, ErrorResponse{Errors: []string{ERROR_GRAPHQL_TOO_LARGE}})
+			} else {
+				ReturnErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Errors: []string{ERROR_BAD_GRAPHQL_REQUEST}})
+			}
+			return
+		}
+	} else {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				ReturnErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Errors: []string{ERROR_BAD_GRAPHQL_REQUEST}})
+				return
+			}
+		}
+	}
+
+	cache := &docCache{docs: map[string]*goquery.Document{}, accounts: map[string]Account{}}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         graphQLSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        context.WithValue(r.Context(), docCacheKey{}, cache),
+	})
+
+	// Warnings about the career page's layout (see CheckLayout) are listed in the extensions of the result.
+	if warnings := parseWarningsFromContext(r.Context()); len(warnings) > 0 {
+		result.Extensions = map[string]interface{}{"warnings": warnings}
+	}
+
+	response, err := json.Marshal(result)
+	if err != nil {
+		panic(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response)
+}
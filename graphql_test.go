@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLRequestTooLarge(t *testing.T) {
+	body := `{"query": "` + strings.Repeat(" ", MAX_GRAPHQL_SIZE) + `{ search(tag: \"Tester-1234\") { tag } }"}`
+
+	recorder := httptest.NewRecorder()
+	GraphQLHandler(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got HTTP %d, want 413: %s", recorder.Code, recorder.Body)
+	}
+}
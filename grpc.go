@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"github.com/KyleCrowley/goverwatch/goverwatchpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"strings"
+)
+
+// grpcServer implements the Goverwatch gRPC service (see goverwatchpb/goverwatch.proto).
+// It shares the scraping core with the REST handlers, so both always return the same data.
+type grpcServer struct {
+	goverwatchpb.UnimplementedGoverwatchServer
+}
+
+// newGRPCServer returns a gRPC server with the Goverwatch service registered.
+func newGRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcRecoveryInterceptor, grpcRateLimitInterceptor, grpcAPIKeyInterceptor))
+	goverwatchpb.RegisterGoverwatchServer(server, &grpcServer{})
+	return server
+}
+
+// grpcRecoveryInterceptor recovers from any panic raised while handling the call, so the caller gets an Internal
+// error instead of the server crashing (or a FailedPrecondition error if the career page's layout changed, see
+// LayoutError, and an Unavailable error if the Overwatch site is failing, see UpstreamUnavailableError). It is the gRPC
+// equivalent of RecoveryMiddleware.
+// Each call also gets a requestInfo, so any warnings about the career page's layout are sent back in the
+// "x-parse-warnings" header.
+func grpcRecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	call := &requestInfo{ID: newRequestID()}
+	ctx = context.WithValue(ctx, requestInfoKey{}, call)
+
+	defer func() {
+		if r := recover(); r != nil {
+			if layoutErr, ok := r.(*LayoutError); ok {
+				logger.Error("upstream layout changed",
+					"request_id", call.ID,
+					"method", info.FullMethod,
+					"parser", layoutErr.Parser,
+					"missing", layoutErr.Missing,
+				)
+
+				// Not Internal, so callers can tell the Overwatch site changing apart from a bug in the service.
+				err = status.Error(codes.FailedPrecondition, ERROR_UPSTREAM_LAYOUT_CHANGED+" "+layoutErr.Error())
+				return
+			}
+
+			if unavailableErr, ok := r.(*UpstreamUnavailableError); ok {
+				logger.Warn("upstream unavailable",
+					"request_id", call.ID,
+					"method", info.FullMethod,
+					"target", unavailableErr.Target,
+				)
+
+				err = status.Error(codes.Unavailable, ERROR_UPSTREAM_UNAVAILABLE)
+				return
+			}
+
+			logger.Error("panic",
+				"request_id", call.ID,
+				"method", info.FullMethod,
+				"error", r,
+				"stack", string(debug.Stack()),
+			)
+
+			err = status.Error(codes.Internal, ERROR_INTERNAL)
+		}
+	}()
+
+	res, err = handler(ctx, req)
+
+	if warnings := call.parseWarnings(); len(warnings) > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(PARSE_WARNINGS_HEADER), strings.Join(warnings, "; ")))
+	}
+
+	return res, err
+}
+
+// getGRPCPlayer returns the player of the call, after checking that the platform, region and (if not empty) mode are
+// valid and that the player exists. This does the same job as PRTMiddleware, PRTMMiddleware and
+// PlayerNotFoundMiddleware do for the REST API.
+func getGRPCPlayer(ctx context.Context, platform string, region string, tag string, mode *string) (*Player, error) {
+	p := &Player{Platform: platform, Region: region, Tag: tag, ctx: ctx}
+
+	errors := []string{}
+	if !p.platformIsValid() {
+		errors = append(errors, ERROR_BAD_PLATFORM)
+	}
+
+	if !p.regionIsValid() {
+		errors = append(errors, ERROR_BAD_REGION)
+	}
+
+	if mode != nil {
+		*mode = strings.ToLower(*mode)
+		if !modeIsValid(*mode) {
+			errors = append(errors, ERROR_BAD_MODE)
+		}
+	}
+
+	if len(errors) > 0 {
+		return nil, status.Error(codes.InvalidArgument, strings.Join(errors, ", "))
+	}
+
+	accounts := p.GetAccountByName()
+	if len(accounts) == 0 {
+		return nil, status.Error(codes.NotFound, ERROR_PLAYER_NOT_FOUND)
+	}
+
+	// Keep the search results, so GetMatchingAccount doesn't search again.
+	p.ctx = withAccounts(ctx, accounts)
+
+	return p, nil
+}
+
+func (s *grpcServer) Search(ctx context.Context, req *goverwatchpb.SearchRequest) (*goverwatchpb.SearchResponse, error) {
+	p := &Player{Tag: req.GetTag(), ctx: ctx}
+
+	res := &goverwatchpb.SearchResponse{}
+	for _, result := range GetSearchResults(p.GetAccountByName()) {
+		res.Results = append(res.Results, &goverwatchpb.SearchResult{
+			Platform: result.Platform,
+			Region:   result.Region,
+			Tag:      result.Tag,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) GetProfile(ctx context.Context, req *goverwatchpb.PlayerRequest) (*goverwatchpb.Profile, error) {
+	p, err := getGRPCPlayer(ctx, req.GetPlatform(), req.GetRegion(), req.GetTag(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.GetProfileDoc()
+	CheckLayout(ctx, d, PARSER_PROFILE, "")
+
+	profile := GetProfile(d, p, p.GetMatchingAccount())
+
+	return &goverwatchpb.Profile{
+		Username: profile.Username,
+		Avatar:   profile.Avatar,
+		Level: &goverwatchpb.Level{
+			Displayed:     profile.Level.Displayed,
+			Actual:        int32(profile.Level.Actual),
+			Stars:         int32(profile.Level.Stars),
+			Tier:          profile.Level.Tier,
+			Portrait:      profile.Level.Portrait,
+			StarsPortrait: profile.Level.StarsPortrait,
+		},
+		Modes: &goverwatchpb.Modes{
+			Quickplay:   modeToProto(profile.Modes.Quickplay),
+			Competitive: modeToProto(profile.Modes.Competitive),
+		},
+		Competitive: competitiveRankToProto(profile.Competitive),
+	}, nil
+}
+
+func (s *grpcServer) GetAchievements(ctx context.Context, req *goverwatchpb.PlayerRequest) (*goverwatchpb.AchievementsResponse, error) {
+	p, err := getGRPCPlayer(ctx, req.GetPlatform(), req.GetRegion(), req.GetTag(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.GetProfileDoc()
+	CheckLayout(ctx, d, PARSER_ACHIEVEMENTS, "")
+
+	res := &goverwatchpb.AchievementsResponse{}
+	for _, achievement := range GetAchievements(d) {
+		res.Achievements = append(res.Achievements, &goverwatchpb.Achievement{
+			Title:       achievement.Title,
+			Description: achievement.Description,
+			ImageUrl:    achievement.ImageURL,
+			Finished:    achievement.Finished,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) GetAllHeroStats(ctx context.Context, req *goverwatchpb.ModeRequest) (*goverwatchpb.StatsResponse, error) {
+	mode := req.GetMode()
+	p, err := getGRPCPlayer(ctx, req.GetPlatform(), req.GetRegion(), req.GetTag(), &mode)
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.GetProfileDoc()
+	CheckLayout(ctx, d, PARSER_ALL_HERO_STATS, mode)
+
+	return statsToProto(GetAllHeroStats(d, mode)), nil
+}
+
+func (s *grpcServer) GetHeroBreakdown(ctx context.Context, req *goverwatchpb.ModeRequest) (*goverwatchpb.HeroBreakdownResponse, error) {
+	mode := req.GetMode()
+	p, err := getGRPCPlayer(ctx, req.GetPlatform(), req.GetRegion(), req.GetTag(), &mode)
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.GetProfileDoc()
+	CheckLayout(ctx, d, PARSER_HEROS_BREAKDOWN, mode)
+
+	res := &goverwatchpb.HeroBreakdownResponse{Stats: map[string]*goverwatchpb.HeroBreakdownList{}}
+	for name, breakdowns := range GetHeroBreakdowns(d, mode) {
+		list := &goverwatchpb.HeroBreakdownList{}
+		for _, breakdown := range breakdowns {
+			list.Heroes = append(list.Heroes, &goverwatchpb.HeroBreakdown{
+				Hero:       breakdown.Hero,
+				Image:      breakdown.Image,
+				Value:      breakdown.Value,
+				Percentage: breakdown.Percentage,
+			})
+		}
+		res.Stats[name] = list
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) GetHeroStats(ctx context.Context, req *goverwatchpb.HeroRequest) (*goverwatchpb.StatsResponse, error) {
+	mode := req.GetMode()
+	p, err := getGRPCPlayer(ctx, req.GetPlatform(), req.GetRegion(), req.GetTag(), &mode)
+	if err != nil {
+		return nil, err
+	}
+
+	d := p.GetProfileDoc()
+	CheckLayout(ctx, d, PARSER_HERO_STATS, mode)
+
+	hex, ok := GetHeroHexMap(d)[strings.ToLower(req.GetHero())]
+	if !ok {
+		return nil, status.Error(codes.NotFound, ERROR_HERO_NOT_FOUND)
+	}
+
+	return statsToProto(GetHeroStats(d, mode, hex)), nil
+}
+
+func modeToProto(m Mode) *goverwatchpb.Mode {
+	return &goverwatchpb.Mode{
+		Won:    int32(m.Won),
+		Lost:   int32(m.Lost),
+		Played: int32(m.Played),
+		Time:   m.Time,
+	}
+}
+
+// competitiveRankToProto returns nil if the player is unranked (rank is nil).
+func competitiveRankToProto(rank *CompetitiveRank) *goverwatchpb.CompetitiveRank {
+	if rank == nil {
+		return nil
+	}
+
+	res := &goverwatchpb.CompetitiveRank{
+		Rank:        rank.Rank,
+		RankImg:     rank.RankImg,
+		SkillRating: int32(rank.SkillRating),
+		Tier:        rank.Tier,
+	}
+	for _, role := range rank.Roles {
+		res.Roles = append(res.Roles, &goverwatchpb.RoleRank{
+			Role:        role.Role,
+			SkillRating: int32(role.SkillRating),
+			Tier:        role.Tier,
+			RankImg:     role.RankImg,
+		})
+	}
+	return res
+}
+
+func statsToProto(stats []Stat) *goverwatchpb.StatsResponse {
+	res := &goverwatchpb.StatsResponse{}
+	for _, stat := range stats {
+		res.Stats = append(res.Stats, &goverwatchpb.Stat{
+			Name:            stat.Name,
+			Value:           stat.Value,
+			SectionName:     stat.SectionName,
+			CanonicalName:   stat.CanonicalName,
+			StatKey:         stat.Key,
+			StatKeyUnstable: stat.KeyUnstable,
+		})
+	}
+	return res
+}
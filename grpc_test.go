@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"github.com/KyleCrowley/goverwatch/goverwatchpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testCareerPage = `<html><body><div><div class="profile-background">
+<div class="header-masthead">Tester</div>
+<img class="player-portrait" src="https://example.com/portrait.png">
+<div class="player-level"><div class="u-vertical-center">57</div></div>
+<select data-group-id="stats"><option option-id="Mercy" value="0x02E0000000000004">Mercy</option></select>
+<select data-group-id="comparisons"><option option-id="Time Played" value="0x0860000000000021">Time Played</option></select>
+<div id="quickplay"><table><tr><td>Games Won</td><td>10</td></tr><tr><td>Games Played</td><td>15</td></tr></table>
+<div class="career-stats-section"><div><div class="row" data-category-id="0x02E0000000000004"><div class="card-stat-block"><table>
+<thead><tr><th><h5 class="stat-title">Hero Specific</h5></th></tr></thead>
+<tbody><tr><td>Blaster Kills</td><td>3</td></tr></tbody>
+</table></div></div></div></div></div>
+</div></div></body></html>`
+
+const testSearchResults = `[{"careerLink":"/career/pc/us/Tester-1234","platformDisplayName":"Tester#1234","level":157,"portrait":"https://example.com/portrait.png"}]`
+
+// testChangedSearchResults is a player whose career page has none of the sections every career page has, as if the
+// layout of the Overwatch site changed.
+const testChangedSearchResults = `[{"careerLink":"/career/pc/us/Changed-1","platformDisplayName":"Changed#1","level":1,"portrait":""}]`
+
+// useTestUpstream replaces the Overwatch site, for the rest of the test, by a server knowing a single player
+// ("Tester#1234" on pc/us), and one whose career page has an unknown layout ("Changed#1" on pc/us).
+func useTestUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/Tester-1234":
+			w.Write([]byte(testSearchResults))
+		case "/career/pc/us/Tester-1234":
+			w.Write([]byte(testCareerPage))
+		case "/search/Changed-1":
+			w.Write([]byte(testChangedSearchResults))
+		case "/career/pc/us/Changed-1":
+			w.Write([]byte("<html><body></body></html>"))
+		default:
+			w.Write([]byte("[]"))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	upstreamConfig := config.Upstream
+	config.Upstream.BaseURL = upstream.URL + "/career/"
+	config.Upstream.SearchURL = upstream.URL + "/search/"
+	t.Cleanup(func() { config.Upstream = upstreamConfig })
+}
+
+// newTestGRPCClient returns a client of the gRPC service, served in-process, with the Overwatch site replaced (see
+// useTestUpstream).
+func newTestGRPCClient(t *testing.T) goverwatchpb.GoverwatchClient {
+	useTestUpstream(t)
+
+	listener := bufconn.Listen(1 << 20)
+	server := newGRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return goverwatchpb.NewGoverwatchClient(conn)
+}
+
+func TestGRPCSearch(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	res, err := client.Search(context.Background(), &goverwatchpb.SearchRequest{Tag: "Tester-1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(res.Results))
+	}
+
+	result := res.Results[0]
+	if result.Platform != "pc" || result.Region != "us" || result.Tag != "Tester-1234" {
+		t.Errorf("got result %s/%s/%s, want pc/us/Tester-1234", result.Platform, result.Region, result.Tag)
+	}
+}
+
+func TestGRPCGetProfile(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	profile, err := client.GetProfile(context.Background(), &goverwatchpb.PlayerRequest{
+		Platform: "pc",
+		Region:   "us",
+		Tag:      "Tester-1234",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.Username != "Tester#1234" {
+		t.Errorf("got username %q, want %q", profile.Username, "Tester#1234")
+	}
+
+	if profile.Level.GetActual() != 157 {
+		t.Errorf("got actual level %d, want 157", profile.Level.GetActual())
+	}
+
+	if quickplay := profile.Modes.GetQuickplay(); quickplay.GetWon() != 10 || quickplay.GetLost() != 5 {
+		t.Errorf("got quickplay %d won and %d lost, want 10 won and 5 lost", quickplay.GetWon(), quickplay.GetLost())
+	}
+
+	if profile.Competitive != nil {
+		t.Errorf("got competitive rank %v for an unranked player, want nil", profile.Competitive)
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	client := newTestGRPCClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"invalid platform", func() error {
+			_, err := client.GetProfile(ctx, &goverwatchpb.PlayerRequest{Platform: "n64", Region: "us", Tag: "Tester-1234"})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid region", func() error {
+			_, err := client.GetAchievements(ctx, &goverwatchpb.PlayerRequest{Platform: "pc", Region: "moon", Tag: "Tester-1234"})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid mode", func() error {
+			_, err := client.GetAllHeroStats(ctx, &goverwatchpb.ModeRequest{Platform: "pc", Region: "us", Tag: "Tester-1234", Mode: "arcade"})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown player", func() error {
+			_, err := client.GetProfile(ctx, &goverwatchpb.PlayerRequest{Platform: "pc", Region: "us", Tag: "Nobody-1"})
+			return err
+		}, codes.NotFound},
+		{"unknown hero", func() error {
+			_, err := client.GetHeroStats(ctx, &goverwatchpb.HeroRequest{Platform: "pc", Region: "us", Tag: "Tester-1234", Mode: "quickplay", Hero: "notahero"})
+			return err
+		}, codes.NotFound},
+		{"layout changed", func() error {
+			_, err := client.GetProfile(ctx, &goverwatchpb.PlayerRequest{Platform: "pc", Region: "us", Tag: "Changed-1"})
+			return err
+		}, codes.FailedPrecondition},
+	}
+
+	for _, test := range tests {
+		if code := status.Code(test.call()); code != test.code {
+			t.Errorf("%s: got %s, want %s", test.name, code, test.code)
+		}
+	}
+}
+
+func TestGRPCAPIKey(t *testing.T) {
+	client := newTestGRPCClient(t)
+
+	config.APIKeys = []string{"secret"}
+	t.Cleanup(func() { config.APIKeys = nil })
+
+	req := &goverwatchpb.SearchRequest{Tag: "Tester-1234"}
+
+	if _, err := client.Search(context.Background(), req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("without a key: got %s, want %s", status.Code(err), codes.Unauthenticated)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret")
+	if _, err := client.Search(ctx, req); err != nil {
+		t.Errorf("with a key: got %v, want no error", err)
+	}
+}
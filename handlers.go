@@ -1,12 +1,12 @@
 package main
 
 import (
+	"context"
 	"github.com/PuerkitoBio/goquery"
 	"encoding/json"
 	"net/http"
 	"github.com/gorilla/mux"
 	"strings"
-	"io/ioutil"
 )
 
 type Achievement struct {
@@ -20,6 +20,24 @@ type Stat struct {
 	Name        string `json:"name"`
 	Value       string `json:"value"`
 	SectionName string `json:"section_name"`
+
+	// CanonicalName is the name of the stat the same in the singular and plural (i.e. "Eliminations", where Name is
+	// "Elimination(s)"). See NormalizeStat.
+	CanonicalName string `json:"canonical_name"`
+
+	// Key is the stable, machine-readable name of the stat (see NormalizeStat). It isn't named "key", since that's the
+	// column the CSV and NDJSON formats add for the hero of each stat (see toRecords).
+	Key string `json:"stat_key"`
+
+	// KeyUnstable is set if the stat is missing from STAT_DEFINITIONS, so its key is made from the label as shown and
+	// changes with it (i.e. between the singular and plural).
+	KeyUnstable bool `json:"stat_key_unstable,omitempty"`
+}
+
+// StatSection is a section of stats (i.e. "Combat"), as shown on a stat card of the career page.
+type StatSection struct {
+	Name  string `json:"name"`
+	Stats []Stat `json:"stats"`
 }
 
 type Mode struct {
@@ -29,15 +47,51 @@ type Mode struct {
 	Time   string `json:"time"`
 }
 
+// Level is the player's level. Portrait is the image of the border shown around the level, and StarsPortrait the image
+// of the stars shown below it (empty if the player has no stars).
+type Level struct {
+	Displayed     string `json:"displayed"`
+	Actual        int    `json:"actual"`
+	Stars         int    `json:"stars"`
+	Tier          string `json:"tier"`
+	Portrait      string `json:"portrait"`
+	StarsPortrait string `json:"stars_portrait"`
+}
+
+// CompetitiveRank is the player's rank in the current competitive season.
+// If the player is ranked in each role (role queue), Roles has a rank per role and SkillRating and Tier are those of
+// the highest. Rank and RankImg are the overall rank as shown on the page.
+type CompetitiveRank struct {
+	Rank        string     `json:"rank"`
+	RankImg     string     `json:"rank_img"`
+	SkillRating int        `json:"skill_rating"`
+	Tier        string     `json:"tier"`
+	Roles       []RoleRank `json:"roles,omitempty"`
+}
+
+// RoleRank is the player's competitive rank in a single role (see ROLES).
+type RoleRank struct {
+	Role        string `json:"role"`
+	SkillRating int    `json:"skill_rating"`
+	Tier        string `json:"tier"`
+	RankImg     string `json:"rank_img"`
+}
+
 type Profile struct {
 	Username string `json:"username"`
 	Avatar   string `json:"avatar"`
-	Level    map[string]interface{} `json:"level"`
+	Level    Level `json:"level"`
 	Modes struct {
-		Quickplay   map[string]interface{} `json:"quickplay"`
-		Competitive map[string]interface{} `json:"competitive"`
+		Quickplay   Mode `json:"quickplay"`
+		Competitive Mode `json:"competitive"`
 	} `json:"modes"`
-	Competitive map[string]interface{} `json:"competitive"`
+	Competitive *CompetitiveRank `json:"competitive,omitempty"`
+}
+
+type SearchResult struct {
+	Platform string `json:"platform"`
+	Region   string `json:"region"`
+	Tag      string `json:"tag"`
 }
 
 type Account struct {
@@ -56,15 +110,7 @@ type HeroBreakdown struct {
 
 // GetAccountByName returns a list of matching profiles, in particular, profiles that match the given tag name.
 func (p *Player) GetAccountByName() []Account {
-	res, err := http.Get(p.formatSearchURL())
-	if err != nil {
-		panic(err.Error())
-	}
-
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		panic(err.Error())
-	}
+	body := fetchUpstream(p.context(), UPSTREAM_SEARCH, p.formatSearchURL())
 
 	var a = new([]Account)
 
@@ -80,13 +126,14 @@ func (p *Player) GetAccountByName() []Account {
 // The hex values can be used later as an id to navigate the DOM.
 func GetHeroHexMap(d *goquery.Document) map[string]string {
 	heroMap := map[string]string{}
+	sel := getSelectors().HeroList
 
 	// Each child of the <select> element is an <option> with two notable attributes:
 	// "option-id": hero name
 	// "value": hero hex value
-	d.Find("select[data-group-id='stats']").Children().Each(func(i int, s *goquery.Selection) {
-		k, _ := s.Attr("option-id")
-		v, _ := s.Attr("value")
+	d.Find(sel.Options).Each(func(i int, s *goquery.Selection) {
+		k, _ := s.Attr(sel.NameAttr)
+		v, _ := s.Attr(sel.ValueAttr)
 
 		// For consistency, make all the keys (hero names) lowercase
 		heroMap[strings.ToLower(k)] = v
@@ -95,17 +142,32 @@ func GetHeroHexMap(d *goquery.Document) map[string]string {
 	return heroMap
 }
 
+// GetHeroNames returns the (lowercase) names of the heroes in the hero list, in the order they are listed on the page.
+// Unlike GetHeroHexMap, it can be used where the order of the heroes matters.
+func GetHeroNames(d *goquery.Document) []string {
+	var names []string
+	sel := getSelectors().HeroList
+
+	d.Find(sel.Options).Each(func(i int, s *goquery.Selection) {
+		k, _ := s.Attr(sel.NameAttr)
+		names = append(names, strings.ToLower(k))
+	})
+
+	return names
+}
+
 // GetStatGUIDMap returns a map of stat names and their associated GUID.
 // The GUID can be used later as an id to navigate the DOM.
 func GetStatGUIDMap(d *goquery.Document) map[string]string {
 	statCategoryMap := make(map[string]string)
+	sel := getSelectors().StatList
 
 	// Each child of the <select> element is an <option> with two notable attributes:
 	// "option-id": stat name
 	// "value": stat GUID
-	d.Find("select[data-group-id='comparisons']").Children().Each(func(i int, s *goquery.Selection) {
-		k, _ := s.Attr("option-id")
-		v, _ := s.Attr("value")
+	d.Find(sel.Options).Each(func(i int, s *goquery.Selection) {
+		k, _ := s.Attr(sel.NameAttr)
+		v, _ := s.Attr(sel.ValueAttr)
 
 		statCategoryMap[k] = v
 	})
@@ -120,52 +182,65 @@ func SearchHandler(w http.ResponseWriter, r *http.Request) {
 	// Get tag from request URL.
 	// Pack into a new Player for future use.
 	vars := mux.Vars(r)
-	p := Player{Tag: vars["tag"]}
+	p := Player{Tag: vars["tag"], ctx: r.Context()}
 
 	// Call helper method to get all matching profiles by account name (tag).
-	searchResults := p.GetAccountByName()
+	profiles := GetSearchResults(p.GetAccountByName())
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, profiles)
+}
 
+// GetSearchResults returns the platform, region and tag of each account (search result).
+func GetSearchResults(searchResults []Account) []SearchResult {
 	// Iterate over each search result.
-	profiles := []map[string]string{}
+	profiles := []SearchResult{}
 	for _, v := range searchResults {
-		tempMap := map[string]string{}
-
 		// Career link will follow this format:
 		// /career/pc/us/TAG
 		// Strip off the initial "/" and then split the string at each "/".
 		parts := strings.Split(v.CareerLink[1:], "/")
 
-		// Pack the platform, region and tag into the tempMap.
-		tempMap["platform"] = parts[1]
-		tempMap["region"] = parts[2]
-		tempMap["tag"] = parts[3]
-
-		profiles = append(profiles, tempMap)
+		// Pack the platform, region and tag into a SearchResult.
+		profiles = append(profiles, SearchResult{Platform: parts[1], Region: parts[2], Tag: parts[3]})
 	}
 
-	// Call helper function to marshal the slice to JSON.
-	MarshalAndHandleErrors(w, r, profiles)
+	return profiles
 }
 
 // AchievementsHandler retrieves all achievements for the given player and returns a JSON array of all achievements.
 // This method will return all achievements, completed or not, but contains a field ("finished") to determine if the
 // player completed the achievement.
 func AchievementsHandler(w http.ResponseWriter, r *http.Request) {
-	achievements := []Achievement{}
-
 	// Get the platform, region and tag from the request URL.
 	// Pack these into a new Player for future use.
-	vars := mux.Vars(r)
-	p := getPlayer(vars)
+	p := getPlayer(r)
+
+	// Call helper function to get all achievements.
+	d := p.GetProfileDoc()
+
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_ACHIEVEMENTS, "")
+
+	achievements := GetAchievements(d)
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, achievements)
+}
+
+// GetAchievements returns all achievements, completed or not, found in the player's HTML document.
+func GetAchievements(d *goquery.Document) []Achievement {
+	achievements := []Achievement{}
+	sel := getSelectors().Achievements
 
 	// Find the parent achievement section, and iterate over all children (each achievement).
-	p.GetProfileDoc().Find("#achievements-section .toggle-display .media-card").Each(func(i int, s *goquery.Selection) {
-		imageURL, _ := s.ChildrenFiltered("img").Attr("src")
-		title, _ := s.ChildrenFiltered(".media-card-caption").ChildrenFiltered(".media-card-title").Html()
-		finished := s.HasClass("m-disabled")
+	d.Find(sel.Cards).Each(func(i int, s *goquery.Selection) {
+		imageURL, _ := s.ChildrenFiltered(sel.Image).Attr("src")
+		title, _ := s.Find(sel.Title).Html()
+		finished := s.HasClass(sel.FinishedClass)
 
-		dataTooltip, _ := s.Attr("data-tooltip")
-		description, _ := s.Parent().ChildrenFiltered("#" + dataTooltip).ChildrenFiltered("p").Html()
+		dataTooltip, _ := s.Attr(sel.TooltipAttr)
+		description, _ := s.Parent().ChildrenFiltered("#" + dataTooltip).ChildrenFiltered(sel.Description).Html()
 
 		achievement := Achievement{
 			Title:       title,
@@ -177,8 +252,7 @@ func AchievementsHandler(w http.ResponseWriter, r *http.Request) {
 		achievements = append(achievements, achievement)
 	})
 
-	// Call helper function to marshal the slice to JSON.
-	MarshalAndHandleErrors(w, r, achievements)
+	return achievements
 }
 
 // ProfileHandler retrieves the player's profile "overview", with statistics like player level, playtime, wins, etc.
@@ -186,13 +260,48 @@ func AchievementsHandler(w http.ResponseWriter, r *http.Request) {
 func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	// Get the platform, region and tag from the request URL.
 	// Pack these into a new Player for future use.
-	vars := mux.Vars(r)
-	p := getPlayer(vars)
+	p := getPlayer(r)
 
-	profile := Profile{}
+	// Call helper function to build the profile.
+	d := p.GetProfileDoc()
 
-	// Call helper method to get all matching profiles by account name (tag).
-	accounts := p.GetAccountByName()
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_PROFILE, "")
+
+	profile := GetProfile(d, p, p.GetMatchingAccount())
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, profile)
+}
+
+// accountsKey is the context key of the player's search results, once they have been searched for to check that the
+// player exists (see withAccounts).
+type accountsKey struct{}
+
+// withAccounts returns a copy of the context holding the player's search results, so GetMatchingAccount doesn't search
+// for them again.
+func withAccounts(ctx context.Context, accounts []Account) context.Context {
+	return context.WithValue(ctx, accountsKey{}, accounts)
+}
+
+// GetMatchingAccount returns the account (search result) matching the player's platform, region and tag.
+// The search results already in the player's context are used, if any (see withAccounts).
+func (p *Player) GetMatchingAccount() Account {
+	accounts, ok := p.context().Value(accountsKey{}).([]Account)
+	if !ok {
+		// Call helper method to get all matching profiles by account name (tag).
+		accounts = p.GetAccountByName()
+	}
+
+	return p.FindMatchingAccount(accounts)
+}
+
+// FindMatchingAccount returns the account of the search results matching the player's platform, region and tag.
+// If none match, the first account is returned, or an empty account if there are no search results at all.
+func (p *Player) FindMatchingAccount(accounts []Account) Account {
+	if len(accounts) == 0 {
+		return Account{}
+	}
 
 	// NOTE: GetAccountByName will return multiple results, so we need to iterate over the results to find the
 	// matching profile
@@ -208,77 +317,80 @@ func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	d := p.GetProfileDoc()
+	return matchingProfile
+}
 
-	// Maps to hold various data, broken down by logical sections.
-	quickplayMap := make(map[string]interface{})
-	competitiveMap := make(map[string]interface{})
-	compRankMap := make(map[string]interface{})
-	levelMap := make(map[string]interface{})
+// GetProfile returns the player's profile "overview" found in the player's HTML document.
+// The matching account is needed for the player's actual level, which is not shown on the career page.
+func GetProfile(d *goquery.Document, p *Player, matchingProfile Account) Profile {
+	profile := Profile{}
+	sel := getSelectors().Profile
+
+	// modeStat returns the value of the stat (by label) in the summary of the mode.
+	modeStat := func(mode string, label string) string {
+		value, _ := d.Find(fill(sel.ModeStat, "{mode}", mode, "{stat}", label)).Next().Html()
+		return value
+	}
 
-	username := d.Find(".header-masthead").Text()
-	avatar, _ := d.Find(".player-portrait").Attr("src")
+	username := d.Find(sel.Username).Text()
+	avatar, _ := d.Find(sel.Avatar).Attr("src")
 
-	quickplayGamesWon, _ := d.Find("#quickplay td:contains('Games Won')").Next().Html()
+	quickplayGamesWon := modeStat("quickplay", sel.GamesWonLabel)
 	if quickplayGamesWon != "" {
-		quickplayMap["won"] = TrimToInt(quickplayGamesWon)
+		profile.Modes.Quickplay.Won = TrimToInt(quickplayGamesWon)
 	}
 
-	quickplayGamesPlayed, _ := d.Find("#quickplay td:contains('Games Played')").Next().Html()
+	quickplayGamesPlayed := modeStat("quickplay", sel.GamesPlayedLabel)
 	if quickplayGamesPlayed != "" {
-		quickplayMap["played"] = TrimToInt(quickplayGamesPlayed)
+		profile.Modes.Quickplay.Played = TrimToInt(quickplayGamesPlayed)
 	}
 
-	quickplayTimePlayed, _ := d.Find("#quickplay td:contains('Time Played')").Next().Html()
+	quickplayTimePlayed := modeStat("quickplay", sel.TimePlayedLabel)
 	if quickplayTimePlayed != "" {
-		quickplayMap["time"] = TrimToString(quickplayTimePlayed)
+		profile.Modes.Quickplay.Time = TrimToString(quickplayTimePlayed)
 	}
 
 	if quickplayGamesPlayed != "" && quickplayGamesWon != "" {
-		quickplayMap["lost"] = TrimToInt(quickplayGamesPlayed) - TrimToInt(quickplayGamesWon)
+		profile.Modes.Quickplay.Lost = TrimToInt(quickplayGamesPlayed) - TrimToInt(quickplayGamesWon)
 	}
 
-	competitiveGamesWon, _ := d.Find("#competitive td:contains('Games Won')").Next().Html()
+	competitiveGamesWon := modeStat("competitive", sel.GamesWonLabel)
 	if competitiveGamesWon != "" {
-		competitiveMap["won"] = TrimToInt(competitiveGamesWon)
+		profile.Modes.Competitive.Won = TrimToInt(competitiveGamesWon)
 	}
 
-	competitiveGamesPlayed, _ := d.Find("#competitive td:contains('Games Played')").Next().Html()
+	competitiveGamesPlayed := modeStat("competitive", sel.GamesPlayedLabel)
 	if competitiveGamesPlayed != "" {
-		competitiveMap["played"] = TrimToInt(competitiveGamesPlayed)
+		profile.Modes.Competitive.Played = TrimToInt(competitiveGamesPlayed)
 	}
 
-	competitiveTimePlayed, _ := d.Find("#competitive td:contains('Time Played')").Next().Html()
+	competitiveTimePlayed := modeStat("competitive", sel.TimePlayedLabel)
 	if competitiveTimePlayed != "" {
-		competitiveMap["time"] = TrimToString(competitiveTimePlayed)
+		profile.Modes.Competitive.Time = TrimToString(competitiveTimePlayed)
 	}
 
 	if competitiveGamesPlayed != "" && competitiveGamesWon != "" {
-		competitiveMap["lost"] = TrimToInt(competitiveGamesPlayed) - TrimToInt(competitiveGamesWon)
+		profile.Modes.Competitive.Lost = TrimToInt(competitiveGamesPlayed) - TrimToInt(competitiveGamesWon)
 	}
 
-	competitiveRankElm := d.Find(".competitive-rank")
-	if competitiveRankElm != nil {
-		rank, _ := d.Find(".competitive-rank div").Html()
-		rankImg, _ := d.Find(".competitive-rank img").Attr("src")
+	// Call helper function to get the competitive rank (if the player is ranked).
+	profile.Competitive = GetCompetitiveRank(d)
 
-		compRankMap["rank"] = TrimToString(rank)
-		compRankMap["rank_img"] = TrimToString(rankImg)
-	}
-
-	levelElm := d.Find(".player-level")
+	levelElm := d.Find(sel.Level)
 	level := levelElm.First().Text()
 
+	// The border and stars are both background images, so take their URLs from the style attributes.
 	levelPortrait, _ := levelElm.Attr("style")
-	// Format of the style is "background-image:url({URL})", so only take the slice with the actual URL in it.
-	levelPortrait = levelPortrait[21:109]
-
-	// TODO: Star Portrait
-
-	levelMap["displayed"] = TrimToString(level)
-	levelMap["actual"] = matchingProfile.Level
-	levelMap["stars"] = CalculateStars(matchingProfile.Level)
-	levelMap["portrait"] = levelPortrait
+	starsPortrait, _ := d.Find(sel.LevelStars).Attr("style")
+
+	profile.Level = Level{
+		Displayed:     TrimToString(level),
+		Actual:        matchingProfile.Level,
+		Stars:         CalculateStars(matchingProfile.Level),
+		Tier:          CalculatePrestigeTier(matchingProfile.Level),
+		Portrait:      GetBackgroundImageURL(levelPortrait),
+		StarsPortrait: GetBackgroundImageURL(starsPortrait),
+	}
 
 	profile.Username = username
 
@@ -289,105 +401,158 @@ func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	profile.Avatar = avatar
-	profile.Level = levelMap
-	profile.Modes.Quickplay = quickplayMap
-	profile.Modes.Competitive = competitiveMap
-	profile.Competitive = compRankMap
 
-	// Call helper function to marshal the slice to JSON.
-	MarshalAndHandleErrors(w, r, profile)
+	return profile
+}
+
+// GetCompetitiveRank returns the player's competitive rank, or nil if the player is unranked.
+// If the player is ranked in each role, the skill rating and tier are those of their highest role rank.
+func GetCompetitiveRank(d *goquery.Document) *CompetitiveRank {
+	sel := getSelectors().Profile
+
+	if d.Find(sel.CompetitiveRank).Length() == 0 {
+		return nil
+	}
+
+	// Iterate over each role rank, in the order they are shown. Roles the player isn't ranked in are skipped.
+	var roles []RoleRank
+	d.Find(sel.CompetitiveRoleRanks).Each(func(i int, s *goquery.Selection) {
+		skillRating := TrimToInt(s.Find(sel.CompetitiveRoleRankValue).Text())
+		if skillRating == 0 {
+			return
+		}
+
+		// The role is only named in a tooltip (i.e. "Tank Skill Rating").
+		tooltip, _ := s.Find(sel.CompetitiveRoleRankRole).Attr(sel.CompetitiveRoleAttr)
+		rankImg, _ := s.Find(sel.CompetitiveRoleRankImage).Attr("src")
+
+		roles = append(roles, RoleRank{
+			Role:        GetRoleFromLabel(tooltip),
+			SkillRating: skillRating,
+			Tier:        CalculateSkillTier(skillRating),
+			RankImg:     TrimToString(rankImg),
+		})
+	})
+
+	// Rank and RankImg are kept as shown on the page, as they were before skill ratings were parsed.
+	rank, _ := d.Find(sel.CompetitiveRankValue).Html()
+	rankImg, _ := d.Find(sel.CompetitiveRankImage).Attr("src")
+
+	competitiveRank := &CompetitiveRank{
+		Rank:    TrimToString(rank),
+		RankImg: TrimToString(rankImg),
+	}
+
+	if len(roles) > 0 {
+		highest := roles[0]
+		for _, role := range roles[1:] {
+			if role.SkillRating > highest.SkillRating {
+				highest = role
+			}
+		}
+
+		competitiveRank.SkillRating = highest.SkillRating
+		competitiveRank.Tier = highest.Tier
+		competitiveRank.Roles = roles
+		return competitiveRank
+	}
+
+	// The rank is shown (without a skill rating) during placement matches, which is still unranked.
+	skillRating := TrimToInt(rank)
+	if skillRating == 0 {
+		return nil
+	}
+
+	competitiveRank.SkillRating = skillRating
+	competitiveRank.Tier = CalculateSkillTier(skillRating)
+	return competitiveRank
 }
 
 // AllHeroStatsHandler retrieves the stats for all hero's combined and returns a JSON array of all stats and their
 // section name.
 func AllHeroStatsHandler(w http.ResponseWriter, r *http.Request) {
-	var stats []Stat
-
 	// Get the platform, region and tag from the request URL.
 	// Pack these into a new Player for future use.
 	vars := mux.Vars(r)
-	p := getPlayer(vars)
+	p := getPlayer(r)
 
 	// Get mode from request URL.
 	mode := strings.ToLower(vars["mode"])
 
-	// Get each stat card (stat section). s will be each card.
-	p.GetProfileDoc().Find("#" + mode + " .career-stats-section div .row[data-category-id='0x02E00000FFFFFFFF'] div").Children().Each(func(i int, s *goquery.Selection) {
-		// Get the section name (i.e. "Combat", "Assists", etc).
-		sectionName := s.Find(".card-stat-block > table > thead > tr > th .stat-title").Text()
-
-		// Iterate over each row in the the table (each row of the stat section).
-		s.Find(".card-stat-block table > tbody > tr").Each(func(j int, t *goquery.Selection) {
-			statName, _ := t.Find("td:nth-child(1)").Html()
-			statName = TrimToString(statName)
-
-			statValue, _ := t.Find("td:nth-child(2)").Html()
-			statValue = TrimToString(statValue)
+	d := p.GetProfileDoc()
 
-			// statName might match the format: "overwatch.guid.XXXX..."
-			// In this case, skip the stat.
-			if strings.HasPrefix(statName, "overwatch.guid") {
-				return
-			}
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_ALL_HERO_STATS, mode)
 
-			// A trailing 's' is added if the value of the stat is greater than 1.
-			// If there is a trailing "s", replace it with "(s)".
-			if statName[len(statName)-1:] == "s" {
-				statName = statName[:len(statName)-1] + "(s)"
-			}
-
-			stat := Stat{
-				Name:        statName,
-				Value:       statValue,
-				SectionName: sectionName,
-			}
+	// The stats can also be grouped by section, if the caller asks for it.
+	if groupBySection(r) {
+		MarshalAndHandleErrors(w, r, GetStatSections(d, mode, ALL_HEROES_CATEGORY_ID))
+		return
+	}
 
-			stats = append(stats, stat)
-		})
-	})
+	// Call helper function to get the combined stats.
+	stats := GetAllHeroStats(d, mode)
 
 	// Call helper function to marshal the slice to JSON.
 	MarshalAndHandleErrors(w, r, stats)
 }
 
+// GetAllHeroStats returns the stats (and their section name) for all hero's combined in the given mode.
+func GetAllHeroStats(d *goquery.Document, mode string) []Stat {
+	return flattenStatSections(GetStatSections(d, mode, ALL_HEROES_CATEGORY_ID))
+}
+
 // HerosHandler retrieves the breakdown of each stat by hero. Each stat is the key, and the value is a JSON array
 // containing the value & percentage for each hero.
 // Essentially, this method breaks-down each stat on a per-hero basis.
 func HerosHandler(w http.ResponseWriter, r *http.Request) {
-	statMap := make(map[string][]HeroBreakdown)
-
 	// Get the platform, region and tag from the request URL.
 	// Pack these into a new Player for future use.
 	vars := mux.Vars(r)
-	p := getPlayer(vars)
+	p := getPlayer(r)
 
 	// Get mode from request URL.
-	mode := vars["mode"]
+	mode := strings.ToLower(vars["mode"])
 
+	// Call helper function to break down each stat by hero.
 	d := p.GetProfileDoc()
 
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_HEROS_BREAKDOWN, mode)
+
+	statMap := GetHeroBreakdowns(d, mode)
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, statMap)
+}
+
+// GetHeroBreakdowns returns a map of stat names and the breakdown of that stat for each hero in the given mode.
+func GetHeroBreakdowns(d *goquery.Document, mode string) map[string][]HeroBreakdown {
+	statMap := make(map[string][]HeroBreakdown)
+
 	// Call helper function to get the GUID of each stat.
 	// The GUID will be used to find the HTML of each stat.
 	statGUIDMap := GetStatGUIDMap(d)
 
-	row := d.Find("body > div > .profile-background > #" + mode + " > .hero-comparison-section .row.column")
+	sel := getSelectors().HeroComparison
+	row := d.Find(fill(sel.Section, "{mode}", mode))
 
 	// Iterate over the map (each stat), and find the associated HTML nodes.
 	for k, v := range statGUIDMap {
 		// Temp slice to hold each hero's breakdown.
 		var breakdownList []HeroBreakdown
 
-		row.Find("div[data-category-id='" + v + "']").Children().Each(func(i int, bar *goquery.Selection) {
-			percent, _ := bar.Attr("data-overwatch-progress-percent")
+		row.Find(fill(sel.Bars, "{category}", v)).Children().Each(func(i int, bar *goquery.Selection) {
+			percent, _ := bar.Attr(sel.PercentAttr)
 			percent = TrimToString(percent)
 
-			image, _ := bar.ChildrenFiltered("img").Attr("src")
+			image, _ := bar.ChildrenFiltered(sel.Image).Attr("src")
 			image = TrimToString(image)
 
-			heroName := bar.Find(".bar-container .bar-text .title").Text()
+			heroName := bar.Find(sel.Hero).Text()
 			heroName = TrimToString(heroName)
 
-			value := bar.Find(".bar-container .bar-text .description").Text()
+			value := bar.Find(sel.Value).Text()
 			value = TrimToString(value)
 
 			breakdownList = append(breakdownList, HeroBreakdown{heroName, image, value, TrimToFloat(percent)})
@@ -398,8 +563,7 @@ func HerosHandler(w http.ResponseWriter, r *http.Request) {
 		statMap[k] = breakdownList
 	}
 
-	// Call helper function to marshal the slice to JSON.
-	MarshalAndHandleErrors(w, r, statMap)
+	return statMap
 }
 
 // HeroHandler retrieves the stats for the given hero (by name) and returns a JSON array of all of the stats and
@@ -407,12 +571,10 @@ func HerosHandler(w http.ResponseWriter, r *http.Request) {
 // This method is similar to AllHeroStatsHandler, with the except that the stats shown are for the hero itself,
 // rather that a combined total.
 func HeroHandler(w http.ResponseWriter, r *http.Request) {
-	var stats []Stat
-
 	// Get the platform, region and tag from the request URL.
 	// Pack these into a new Player for future use.
 	vars := mux.Vars(r)
-	p := getPlayer(vars)
+	p := getPlayer(r)
 
 	// Get mode and the hero name from request URL.
 	mode := strings.ToLower(vars["mode"])
@@ -420,55 +582,163 @@ func HeroHandler(w http.ResponseWriter, r *http.Request) {
 
 	d := p.GetProfileDoc()
 
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_HERO_STATS, mode)
+
 	// Call helper function to get the hero hex map.
 	// The hex of the hero will be used as an id to find the matching HTML.
 	heroMap := GetHeroHexMap(d)
 
-	// Find the stat section for the matching mode.
-	row := d.Find("body > div > .profile-background > #" + mode + " > .career-stats-section > div")
-
 	// Get the hex for the hero the user supplied.
 	// TODO: Handle hero name not found
 	hex := heroMap[heroName]
 
-	// Use the hex to find the matching stat section (hero's section).
-	// Then iterate over stat card (stat section).
-	row.ChildrenFiltered(".row[data-category-id='" + hex + "']").Children().Each(func(i int, s *goquery.Selection) {
+	// The stats can also be grouped by section, if the caller asks for it.
+	if groupBySection(r) {
+		MarshalAndHandleErrors(w, r, GetStatSections(d, mode, hex))
+		return
+	}
+
+	// Call helper function to get the stats for the hero's section.
+	stats := GetHeroStats(d, mode, hex)
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, stats)
+}
+
+// AllHerosHandler retrieves the stats for every hero the player has and returns a JSON object keyed by hero name,
+// where each value is a JSON array of the hero's stats and their section name.
+// This method is the equivalent of calling HeroHandler for each hero, but only fetches the profile once.
+func AllHerosHandler(w http.ResponseWriter, r *http.Request) {
+	// Get the platform, region and tag from the request URL.
+	// Pack these into a new Player for future use.
+	vars := mux.Vars(r)
+	p := getPlayer(r)
+
+	// Get mode from request URL.
+	mode := strings.ToLower(vars["mode"])
+
+	// Call helper function to get the stats of every hero.
+	d := p.GetProfileDoc()
+
+	// Call helper function to make sure the career page has the sections the parser needs.
+	CheckLayout(p.context(), d, PARSER_HERO_STATS, mode)
+
+	// The stats can also be grouped by section, if the caller asks for it.
+	if groupBySection(r) {
+		MarshalAndHandleErrors(w, r, GetStatSectionsByHero(d, mode))
+		return
+	}
+
+	heroStats := GetStatsByHero(d, mode)
+
+	// Call helper function to marshal the map to JSON.
+	MarshalAndHandleErrors(w, r, heroStats)
+}
+
+// GetStatsByHero returns a map of hero names and their stats in the given mode.
+// Heroes without any stats (i.e. never played in the given mode) are left out.
+func GetStatsByHero(d *goquery.Document, mode string) map[string][]Stat {
+	heroStats := make(map[string][]Stat)
+
+	for heroName, sections := range GetStatSectionsByHero(d, mode) {
+		heroStats[heroName] = flattenStatSections(sections)
+	}
+
+	return heroStats
+}
+
+// GetStatSectionsByHero returns the stats of every hero (except the combined "all heroes") in the given mode, grouped
+// by section and keyed by hero name. Heroes without any stats are left out.
+func GetStatSectionsByHero(d *goquery.Document, mode string) map[string][]StatSection {
+	heroSections := make(map[string][]StatSection)
+
+	for heroName, hex := range GetHeroHexMap(d) {
+		// The combined stats are already covered by AllHeroStatsHandler.
+		if heroName == ALL_HEROES {
+			continue
+		}
+
+		if sections := GetStatSections(d, mode, hex); len(sections) > 0 {
+			heroSections[heroName] = sections
+		}
+	}
+
+	return heroSections
+}
+
+// GetHeroStats returns the stats (and their section name) for the hero matching the given hex in the given mode.
+func GetHeroStats(d *goquery.Document, mode string, hex string) []Stat {
+	return flattenStatSections(GetStatSections(d, mode, hex))
+}
+
+// GetStatSections returns the stat sections (i.e. "Combat", "Assists", etc.) of the given category in the given mode,
+// in the order they are shown on the page.
+// The category is the data-category-id of the stats, either a hero's hex (see GetHeroHexMap) or
+// ALL_HEROES_CATEGORY_ID for the stats of all heroes combined. Sections without any stats are left out.
+func GetStatSections(d *goquery.Document, mode string, category string) []StatSection {
+	var sections []StatSection
+	sel := getSelectors().Stats
+
+	// Use the mode and category to find the matching stat cards, and iterate over each card (stat section).
+	d.Find(fill(sel.Cards, "{mode}", mode, "{category}", category)).Children().Each(func(i int, s *goquery.Selection) {
 		// Get the section name (i.e. "Combat", "Assists", etc).
-		sectionName := s.Find(".card-stat-block > table > thead > tr > th > .stat-title").Text()
+		section := StatSection{Name: s.Find(sel.Title).Text()}
 
-		// Similar to AllHeroStatsHandler, iterate over each stat in the section's table.
-		s.Find(".card-stat-block table > tbody > tr").Each(func(j int, t *goquery.Selection) {
-			statName, _ := t.Find("td:nth-child(1)").Html()
+		// Iterate over each row in the the table (each row of the stat section).
+		s.Find(sel.Rows).Each(func(j int, t *goquery.Selection) {
+			statName, _ := t.Find(sel.Name).Html()
 			statName = TrimToString(statName)
 
-			statValue, _ := t.Find("td:nth-child(2)").Html()
+			statValue, _ := t.Find(sel.Value).Html()
 			statValue = TrimToString(statValue)
 
-			// statName might match the format: "overwatch.guid.XXXX..."
+			// statName might match the format: "overwatch.guid.XXXX..." or be empty.
 			// In this case, skip the stat.
-			if strings.HasPrefix(statName, "overwatch.guid") {
+			if statName == "" || strings.HasPrefix(statName, "overwatch.guid") {
 				return
 			}
 
+			// The label is in the singular or plural depending on the value, so the canonical name is added as well.
+			statKey, canonicalName, known := NormalizeStat(statName)
+
+			// Only labels from the Overwatch site are logged, not those of saved pages sent to ParseHandler (which
+			// have no URL), since those could be anything.
+			if !known && d.Url != nil {
+				logUnknownStatLabel(canonicalName)
+			}
+
 			// A trailing 's' is added if the value of the stat is greater than 1.
 			// If there is a trailing "s", replace it with "(s)".
 			if statName[len(statName)-1:] == "s" {
 				statName = statName[:len(statName)-1] + "(s)"
 			}
 
-			stat := Stat{
-				Name:        statName,
-				Value:       statValue,
-				SectionName: sectionName,
-			}
-
-			stats = append(stats, stat)
+			section.Stats = append(section.Stats, Stat{
+				Name:          statName,
+				Value:         statValue,
+				SectionName:   section.Name,
+				Key:           statKey,
+				CanonicalName: canonicalName,
+				KeyUnstable:   !known,
+			})
 		})
+
+		if len(section.Stats) > 0 {
+			sections = append(sections, section)
+		}
 	})
 
-	// Call helper function to marshal the slice to JSON.
-	MarshalAndHandleErrors(w, r, stats)
+	return sections
+}
+
+// flattenStatSections returns the stats of every section, in order. Returns nil if there are no stats at all.
+func flattenStatSections(sections []StatSection) []Stat {
+	var stats []Stat
+	for _, section := range sections {
+		stats = append(stats, section.Stats...)
+	}
+	return stats
 }
 
 // ErrorHandler is a generic error handler to respond to various HTTP stats codes.
@@ -480,7 +750,17 @@ func ErrorHandler(w http.ResponseWriter, r *http.Request, statusCode int) {
 }
 
 // MarshalAndHandleErrors is a helper function to marshal data to JSON, while catching errors along the way.
+// List responses can also be written as CSV or NDJSON, if the caller asks for it (see getFormat).
 func MarshalAndHandleErrors(w http.ResponseWriter, r *http.Request, res interface{}) {
+	// The format has already been validated by FormatMiddleware.
+	format := getFormat(r)
+
+	// Warnings about the career page's layout (see CheckLayout) are sent even if nothing was found, since they are
+	// likely the reason why.
+	for _, warning := range parseWarningsFromContext(r.Context()) {
+		w.Header().Add(PARSE_WARNINGS_HEADER, warning)
+	}
+
 	response, err := json.Marshal(res)
 	if err != nil {
 		panic(err)
@@ -491,6 +771,30 @@ func MarshalAndHandleErrors(w http.ResponseWriter, r *http.Request, res interfac
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	if format == FORMAT_JSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+		return
+	}
+
+	// CSV and NDJSON only make sense for lists, so flatten the response into records (rows) first.
+	records, err := toRecords(response)
+	if err != nil {
+		ReturnErrorResponse(w, r, http.StatusNotAcceptable, ErrorResponse{Errors: []string{ERROR_FORMAT_NOT_LIST}})
+		return
+	}
+
+	if format == FORMAT_CSV {
+		response, err = marshalCSV(records)
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		response, err = marshalNDJSON(records)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
 	w.Write(response)
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestModeIsCaseInsensitive(t *testing.T) {
+	useTestUpstream(t)
+	handler := Use(newRouter(), RecoveryMiddleware)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	for _, route := range []string{"all-hero-stats", "heros-breakdown", "roles", "heroes", "hero/mercy"} {
+		want := get("/api/v1/pc/us/Tester-1234/quickplay/" + route)
+		if want.Code != http.StatusOK && want.Code != http.StatusNotFound {
+			t.Fatalf("quickplay/%s: got HTTP %d: %s", route, want.Code, want.Body)
+		}
+
+		for _, mode := range []string{"Quickplay", "QUICKPLAY"} {
+			got := get("/api/v1/pc/us/Tester-1234/" + mode + "/" + route)
+			if got.Code != want.Code || got.Body.String() != want.Body.String() {
+				t.Errorf("%s/%s: got HTTP %d %s, want HTTP %d %s", mode, route, got.Code, got.Body, want.Code, want.Body)
+			}
+		}
+	}
+}
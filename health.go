@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// UPSTREAM_PROBE_TIMEOUT is how long StatusHandler waits for each upstream to respond.
+	UPSTREAM_PROBE_TIMEOUT = 2 * time.Second
+	// UPSTREAM_PROBE_INTERVAL is how long the result of probing an upstream is reused for, so StatusHandler doesn't make
+	// requests to the Overwatch site on every call.
+	UPSTREAM_PROBE_INTERVAL = 30 * time.Second
+)
+
+// upstreamURLs returns a map of each upstream target and the URL used to check if it is reachable.
+func upstreamURLs() map[string]string {
+	return map[string]string{
+		UPSTREAM_PROFILE: config.Upstream.BaseURL,
+		UPSTREAM_SEARCH:  config.Upstream.SearchURL,
+	}
+}
+
+// ready is set once the server is ready to handle requests.
+var ready atomic.Bool
+
+var (
+	lastSuccessMutex sync.Mutex
+	lastSuccess      = map[string]time.Time{}
+)
+
+// probeResult is the result of probing an upstream URL (see probeUpstreams).
+type probeResult struct {
+	err       error
+	checkedAt time.Time
+}
+
+var (
+	probesMutex sync.Mutex
+	probes      = map[string]probeResult{}
+)
+
+type UpstreamStatus struct {
+	Target      string     `json:"target"`
+	URL         string     `json:"url"`
+	Reachable   bool       `json:"reachable"`
+	Error       string     `json:"error,omitempty"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	LastSuccess *time.Time `json:"last_success"`
+	Breaker     string     `json:"breaker"`
+}
+
+type CacheStatus struct {
+	Entries  int     `json:"entries"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+type Status struct {
+	Ready     bool             `json:"ready"`
+	Upstreams []UpstreamStatus `json:"upstreams"`
+	Cache     CacheStatus      `json:"cache"`
+}
+
+// recordUpstreamSuccess remembers the time of the last successful request to the given upstream target.
+func recordUpstreamSuccess(target string) {
+	lastSuccessMutex.Lock()
+	defer lastSuccessMutex.Unlock()
+
+	lastSuccess[target] = time.Now()
+}
+
+// getLastSuccess returns the time of the last successful request to the given upstream target, or nil if there hasn't
+// been one yet.
+func getLastSuccess(target string) *time.Time {
+	lastSuccessMutex.Lock()
+	defer lastSuccessMutex.Unlock()
+
+	t, ok := lastSuccess[target]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// HealthzHandler reports that the process is alive. It never checks anything else, so it can be used as a liveness
+// probe.
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	MarshalAndHandleErrors(w, r, map[string]string{"status": "ok"})
+}
+
+// checkReady returns an error if the server isn't ready to handle requests: the config is loaded and every route
+// registered (see ready), and the storage path (if set) can be written to.
+func checkReady() error {
+	if !ready.Load() {
+		return fmt.Errorf(ERROR_NOT_READY)
+	}
+
+	if config.Storage.Path != "" {
+		f, err := os.CreateTemp(config.Storage.Path, ".readyz-*")
+		if err != nil {
+			return fmt.Errorf("%s %s", ERROR_STORAGE_UNREACHABLE, err)
+		}
+		f.Close()
+
+		if err := os.Remove(f.Name()); err != nil {
+			return fmt.Errorf("%s %s", ERROR_STORAGE_UNREACHABLE, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadyzHandler reports whether the server is ready to handle requests (see checkReady), responding with a HTTP 503
+// error if it isn't. The upstreams aren't checked, since the server can't do anything about them (see StatusHandler).
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkReady(); err != nil {
+		ReturnErrorResponse(w, r, http.StatusServiceUnavailable, ErrorResponse{Errors: []string{err.Error()}})
+		return
+	}
+
+	MarshalAndHandleErrors(w, r, map[string]string{"status": "ready"})
+}
+
+// StatusHandler checks that each upstream (the Overwatch site) is reachable and returns a JSON object with the result
+// of each check, along with the last time a request to that upstream succeeded, the state of its breaker (see
+// circuitBreaker) and the stats of the cache.
+// The checks are shared between calls for UPSTREAM_PROBE_INTERVAL (see probeUpstreams).
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	status := Status{Ready: checkReady() == nil, Upstreams: []UpstreamStatus{}}
+	urls := upstreamURLs()
+	results := probeUpstreams(urls)
+	now := time.Now()
+
+	for _, target := range []string{UPSTREAM_PROFILE, UPSTREAM_SEARCH} {
+		result := results[urls[target]]
+		upstream := UpstreamStatus{
+			Target:      target,
+			URL:         urls[target],
+			Reachable:   result.err == nil,
+			CheckedAt:   result.checkedAt,
+			LastSuccess: getLastSuccess(target),
+			Breaker:     UPSTREAM_BREAKERS[target].state(now),
+		}
+
+		if result.err != nil {
+			upstream.Error = result.err.Error()
+		}
+
+		status.Upstreams = append(status.Upstreams, upstream)
+	}
+
+	status.Cache.Entries = upstreamCache.len()
+	for _, stats := range CACHE_STATS {
+		status.Cache.Hits += stats.hits.Load()
+		status.Cache.Misses += stats.misses.Load()
+	}
+	if lookups := status.Cache.Hits + status.Cache.Misses; lookups > 0 {
+		status.Cache.HitRatio = float64(status.Cache.Hits) / float64(lookups)
+	}
+
+	MarshalAndHandleErrors(w, r, status)
+}
+
+// probeUpstreams returns the result of probing each of the URLs, keyed by URL. A URL is only probed again once its last
+// result is older than UPSTREAM_PROBE_INTERVAL, and the URLs that are probed are probed at the same time.
+// Calls made while probing wait for its results, rather than probing as well.
+func probeUpstreams(urls map[string]string) map[string]probeResult {
+	probesMutex.Lock()
+	defer probesMutex.Unlock()
+
+	now := time.Now()
+	results := map[string]probeResult{}
+	var resultsMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, u := range urls {
+		if result, ok := probes[u]; ok && now.Sub(result.checkedAt) < UPSTREAM_PROBE_INTERVAL {
+			results[u] = result
+			continue
+		}
+
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+
+			result := probeResult{err: probeUpstream(u), checkedAt: now}
+
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+			results[u] = result
+		}(u)
+	}
+
+	wg.Wait()
+
+	for u, result := range results {
+		probes[u] = result
+	}
+	return results
+}
+
+// probeClient is the client used by probeUpstream. Redirects aren't followed, since a redirect already shows that the
+// upstream is up.
+var probeClient = &http.Client{
+	Timeout: UPSTREAM_PROBE_TIMEOUT,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// probeUpstream makes a request to the root of the site the given URL belongs to, returning an error if it doesn't
+// respond with a HTTP 2xx or 3xx status. The URL itself isn't used, since it's only a prefix of the real pages.
+func probeUpstream(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), UPSTREAM_PROBE_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, parsed.Scheme+"://"+parsed.Host+"/", nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := probeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return fmt.Errorf("HTTP %d", res.StatusCode)
+	}
+	return nil
+}
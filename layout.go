@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+)
+
+// PARSE_WARNINGS_HEADER lists the warnings (see CheckLayout) of the response, one header value per warning.
+const PARSE_WARNINGS_HEADER = "X-Parse-Warnings"
+
+// The parsers of the career page, as named in warnings, errors and metrics.
+const (
+	PARSER_PROFILE         = "profile"
+	PARSER_ACHIEVEMENTS    = "achievements"
+	PARSER_ALL_HERO_STATS  = "all_hero_stats"
+	PARSER_HEROS_BREAKDOWN = "heros_breakdown"
+	PARSER_HERO_STATS      = "hero_stats"
+)
+
+// layoutSection is a section of the career page a parser expects to find.
+// "{mode}" in the name and selector is replaced by the mode being parsed (see Selectors).
+type layoutSection struct {
+	Name     string
+	Selector string
+
+	// Required sections are on every career page, so if one is missing, the layout of the page has changed.
+	// Other sections can be legitimately missing (i.e. a mode the player never played, or every mode of a private
+	// profile), so are only warned about.
+	Required bool
+}
+
+// layoutSections returns the sections of the career page each parser expects to find, using the given selectors.
+func layoutSections(s *Selectors) map[string][]layoutSection {
+	return map[string][]layoutSection{
+		PARSER_PROFILE: {
+			{"masthead", s.Profile.Username, true},
+			{"player level", s.Profile.Level, true},
+			{"player portrait", s.Profile.Avatar, false},
+		},
+		PARSER_ACHIEVEMENTS: {
+			{"achievements section", s.Achievements.Section, true},
+			{"achievements", s.Achievements.Cards, false},
+		},
+		PARSER_ALL_HERO_STATS: {
+			{"{mode} section", s.ModeSection, false},
+			{"{mode} all heroes stats", fill(s.Stats.Cards, "{category}", ALL_HEROES_CATEGORY_ID), false},
+		},
+		PARSER_HEROS_BREAKDOWN: {
+			{"{mode} section", s.ModeSection, false},
+			{"stat list", s.StatList.Options, false},
+			{"{mode} hero comparison", s.HeroComparison.Section, false},
+		},
+		PARSER_HERO_STATS: {
+			{"{mode} section", s.ModeSection, false},
+			{"hero list", s.HeroList.Options, true},
+			{"{mode} career stats", s.CareerStatsSection, false},
+		},
+	}
+}
+
+// LayoutError is raised (as a panic) by CheckLayout when a section required by a parser is missing from the career
+// page. RecoveryMiddleware turns it into a HTTP 502 error response, rather than the parser silently returning nothing.
+type LayoutError struct {
+	Parser  string
+	Missing []string
+}
+
+func (e *LayoutError) Error() string {
+	return "upstream layout changed: " + e.Parser + " is missing " + strings.Join(e.Missing, ", ")
+}
+
+// CheckLayout checks that the career page has the sections the parser expects (in the given mode, if it takes one).
+// Missing sections are counted in the metrics. If a required section is missing, a *LayoutError is raised, otherwise
+// a warning for each missing section is added to the request the context belongs to.
+func CheckLayout(ctx context.Context, d *goquery.Document, parser string, mode string) {
+	var missing, warnings []string
+
+	for _, section := range layoutSections(getSelectors())[parser] {
+		name := fill(section.Name, "{mode}", mode)
+		if d.Find(fill(section.Selector, "{mode}", mode)).Length() > 0 {
+			continue
+		}
+
+		// Saved pages (see ParseCareerPage) have no URL, and say nothing about the Overwatch site, so aren't counted.
+		if d.Url != nil {
+			parseMissingSectionsTotal.WithLabelValues(parser, name).Inc()
+		}
+
+		if section.Required {
+			missing = append(missing, name)
+		} else {
+			warnings = append(warnings, parser+": missing "+name)
+		}
+	}
+
+	if len(missing) > 0 {
+		if d.Url != nil {
+			upstreamLayoutChangedTotal.WithLabelValues(parser).Inc()
+		}
+		panic(&LayoutError{Parser: parser, Missing: missing})
+	}
+
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.addParseWarnings(warnings...)
+	}
+}
+
+// parseWarningsFromContext returns the warnings added to the request the context belongs to.
+func parseWarningsFromContext(ctx context.Context) []string {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return info.parseWarnings()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"github.com/PuerkitoBio/goquery"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckLayoutMissingMode(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(testCareerPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The test career page has no competitive section, as if the player never played it (or the profile is private).
+	info := &requestInfo{}
+	ctx := context.WithValue(context.Background(), requestInfoKey{}, info)
+
+	for _, parser := range []string{PARSER_ALL_HERO_STATS, PARSER_HEROS_BREAKDOWN, PARSER_HERO_STATS} {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: got panic %v, want warnings", parser, r)
+				}
+			}()
+
+			CheckLayout(ctx, d, parser, "competitive")
+		}()
+
+		if warning := parser + ": missing competitive section"; !slices.Contains(info.parseWarnings(), warning) {
+			t.Errorf("%s: got warnings %v, want %q", parser, info.parseWarnings(), warning)
+		}
+	}
+}
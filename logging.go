@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+// logger writes structured (JSON) logs to stderr, so they never mix with the output of the command-line tool.
+var logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+// requestInfo holds the details of a single request that are collected while it is handled.
+type requestInfo struct {
+	ID              string
+	upstreamFetches int64
+	cacheHits       int64
+	cacheMisses     int64
+
+	warningsMutex sync.Mutex
+	warnings      []string
+}
+
+type requestInfoKey struct{}
+
+// requestInfoFromContext returns the requestInfo stored in the context by RequestIDMiddleware, or nil if there is none.
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info
+}
+
+// requestIDFromContext returns the ID of the request the context belongs to, or an empty string if there is none.
+func requestIDFromContext(ctx context.Context) string {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return info.ID
+	}
+	return ""
+}
+
+// cacheStatus returns "hit" if every cache lookup of the request was a hit, "miss" if none were, "partial" if only some
+// were, or an empty string if the request didn't look anything up.
+func (info *requestInfo) cacheStatus() string {
+	hits, misses := atomic.LoadInt64(&info.cacheHits), atomic.LoadInt64(&info.cacheMisses)
+	switch {
+	case hits == 0 && misses == 0:
+		return ""
+	case misses == 0:
+		return "hit"
+	case hits == 0:
+		return "miss"
+	}
+	return "partial"
+}
+
+// addParseWarnings adds the warnings (see CheckLayout) to the request, leaving out any it already has.
+func (info *requestInfo) addParseWarnings(warnings ...string) {
+	info.warningsMutex.Lock()
+	defer info.warningsMutex.Unlock()
+
+	for _, warning := range warnings {
+		if !slices.Contains(info.warnings, warning) {
+			info.warnings = append(info.warnings, warning)
+		}
+	}
+}
+
+// parseWarnings returns the warnings added to the request so far.
+func (info *requestInfo) parseWarnings() []string {
+	info.warningsMutex.Lock()
+	defer info.warningsMutex.Unlock()
+
+	return slices.Clone(info.warnings)
+}
+
+// newRequestID returns a random 16 byte (32 character) hex ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RequestIDMiddleware gives each request an ID, which is sent back in the X-Request-ID header.
+// If the caller already supplied an X-Request-ID, it is propagated instead of generating a new one.
+func RequestIDMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(REQUEST_ID_HEADER)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+
+		w.Header().Set(REQUEST_ID_HEADER, id)
+
+		ctx := context.WithValue(r.Context(), requestInfoKey{}, &requestInfo{ID: id})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestLogger returns a middleware that writes an access log for each request handled by the router.
+// The router is used to find the route template (i.e. "/api/{platform}/{region}/{tag}/profile") and the platform,
+// region and tag of the request, since they aren't known before the router has matched the request.
+func RequestLogger(router *mux.Router) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(recorder, r)
+
+			attrs := []any{
+				slog.String("request_id", requestIDFromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			}
+
+			var match mux.RouteMatch
+			if router.Match(r, &match) && match.Route != nil {
+				if template, err := match.Route.GetPathTemplate(); err == nil {
+					attrs = append(attrs, slog.String("route", template))
+				}
+
+				for _, k := range []string{"platform", "region", "tag", "mode"} {
+					if v, ok := match.Vars[k]; ok {
+						attrs = append(attrs, slog.String(k, v))
+					}
+				}
+			}
+
+			if info := requestInfoFromContext(r.Context()); info != nil {
+				attrs = append(attrs, slog.Int64("upstream_fetches", atomic.LoadInt64(&info.upstreamFetches)))
+
+				if cache := info.cacheStatus(); cache != "" {
+					attrs = append(attrs, slog.String("cache", cache))
+				}
+
+				if warnings := info.parseWarnings(); len(warnings) > 0 {
+					attrs = append(attrs, slog.Any("parse_warnings", warnings))
+				}
+			}
+
+			logger.Info("request", attrs...)
+		})
+	}
+}
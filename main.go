@@ -1,43 +1,69 @@
 package main
 
 import (
+	"context"
 	"github.com/gorilla/mux"
 	"net/http"
 	"log"
 	"encoding/json"
 	"os"
-	"github.com/gorilla/handlers"
+	"sync"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
-	PORT := os.Getenv("PORT")
-	if PORT == "" {
-		PORT = "8080"
+	// Settings come from the (optional) config file, with overrides from the environment.
+	c, err := loadConfig(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatal(err)
 	}
+	config = c
+	setSelectors(c.Selectors)
 
-	router := mux.NewRouter().StrictSlash(true)
+	// With a command, run the command-line tool instead of the server.
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
 
-	// "Root" / "Home" route
-	router.HandleFunc("/", home).Methods(http.MethodGet)
+	router := newRouter()
+	if err := checkRouteDocs(router); err != nil {
+		log.Fatal(err)
+	}
 
-	APIRouter := router.PathPrefix("/api").Subrouter()
-	//APIRouter.Path("/patch-notes").HandlerFunc(goverwatch.PatchNoteHandler).Methods(http.MethodGet)
-	APIRouter.Path("/search/{tag}").HandlerFunc(SearchHandler).Methods(http.MethodGet)
+	// Background workers run until the server has shut down, and are waited for before exiting.
+	workers, stopWorkers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
 
-	PRTRouter := APIRouter.PathPrefix("/{platform}/{region}/{tag}").Subrouter()
-	PRTRouter.Handle("/profile", Use(http.HandlerFunc(ProfileHandler), PRTMiddleware, PlayerNotFoundMiddleware)).Methods(http.MethodGet)
-	PRTRouter.Handle("/achievements", Use(http.HandlerFunc(AchievementsHandler), PRTMiddleware, PlayerNotFoundMiddleware)).Methods(http.MethodGet)
+	// The selectors can be changed without a restart, by editing the config file and sending SIGHUP.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		reloadOnSIGHUP(workers, os.Getenv("CONFIG_FILE"))
+	}()
 
-	// Any route under "/{platform}/{region}/{tag}/{mode}"
-	PRTMRouter := APIRouter.PathPrefix("/{platform}/{region}/{tag}/{mode}").Subrouter()
-	PRTMRouter.Handle("/all-hero-stats", Use(http.HandlerFunc(AllHeroStatsHandler), PRTMMiddleware, PlayerNotFoundMiddleware)).Methods(http.MethodGet)
-	PRTMRouter.Handle("/heros-breakdown", Use(http.HandlerFunc(HerosHandler), PRTMMiddleware, PlayerNotFoundMiddleware)).Methods(http.MethodGet)
+	// Responses in storage are removed once they have expired.
+	if config.Storage.Path != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sweepStorage(workers)
+		}()
+	}
 
-	// TODO: Hero name validation
-	PRTMRouter.Handle("/hero/{name}", Use(http.HandlerFunc(HeroHandler), PRTMMiddleware, PlayerNotFoundMiddleware)).Methods(http.MethodGet)
+	// All routes are registered, so the server is ready to handle requests.
+	ready.Store(true)
+
+	logger.Info("listening", "address", config.Server.Address, "tls", config.Server.TLSCertFile != "")
+	err = serve(Use(router, RecoveryMiddleware, MetricsMiddleware(router), RequestLogger(router), RequestIDMiddleware, CORSMiddleware(config.CORS)), config.Server)
+
+	stopWorkers()
+	wg.Wait()
+
+	if err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
 
-	log.Println("Listening on " + PORT)
-	log.Fatal(http.ListenAndServe(":"+PORT, handlers.CORS()(router)))
+	logger.Info("stopped")
 }
 
 func home(w http.ResponseWriter, r *http.Request) {
@@ -49,3 +75,62 @@ func home(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(response)
 }
+
+// newRouter returns a router with every route registered.
+func newRouter() *mux.Router {
+	router := mux.NewRouter().StrictSlash(true)
+
+	// "Root" / "Home" route
+	router.HandleFunc("/", home).Methods(http.MethodGet)
+	router.Handle("/metrics", promhttp.Handler()).Methods(http.MethodGet)
+
+	// Liveness and readiness probes
+	router.HandleFunc("/healthz", HealthzHandler).Methods(http.MethodGet)
+	router.HandleFunc("/readyz", ReadyzHandler).Methods(http.MethodGet)
+
+	// GraphQL over player data
+	router.Handle("/graphql", Use(http.HandlerFunc(GraphQLHandler), APIKeyMiddleware, RateLimitMiddleware)).Methods(http.MethodGet, http.MethodPost)
+
+	// OpenAPI document of every route above and below.
+	openAPI := OpenAPIHandler(router)
+	router.Handle("/api/"+API_VERSION+"/openapi.json", openAPI).Methods(http.MethodGet)
+	router.Handle("/api/openapi.json", openAPI).Methods(http.MethodGet)
+
+	// Versioned API. See "API versioning" in the README for what is guaranteed within a version.
+	// NOTE: "/api/v1" is registered before "/api", so "v1" is never mistaken for a platform.
+	registerAPIRoutes(router.PathPrefix("/api/" + API_VERSION).Subrouter())
+
+	// Unversioned API, kept as an alias of the current version.
+	registerAPIRoutes(router.PathPrefix("/api").Subrouter())
+
+	return router
+}
+
+// registerAPIRoutes registers every API route on the given (sub)router.
+func registerAPIRoutes(APIRouter *mux.Router) {
+	// Every route is rate limited and needs an API key (if any are configured), and then the format is checked before
+	// any other middleware, so a bad format is never fetched for.
+	APIRouter.Use(RateLimitMiddleware, APIKeyMiddleware, FormatMiddleware)
+
+	//APIRouter.Path("/patch-notes").HandlerFunc(goverwatch.PatchNoteHandler).Methods(http.MethodGet)
+	APIRouter.Path("/search/{tag}").HandlerFunc(SearchHandler).Methods(http.MethodGet)
+	APIRouter.Path("/status").HandlerFunc(StatusHandler).Methods(http.MethodGet)
+	APIRouter.Path("/parse").HandlerFunc(ParseHandler).Methods(http.MethodPost)
+
+	// NOTE: The validation middleware is listed after PlayerNotFoundMiddleware, so it is called first and an invalid
+	// request is rejected before the player is searched for.
+	PRTRouter := APIRouter.PathPrefix("/{platform}/{region}/{tag}").Subrouter()
+	PRTRouter.Handle("/profile", Use(http.HandlerFunc(ProfileHandler), PlayerNotFoundMiddleware, PRTMiddleware)).Methods(http.MethodGet)
+	PRTRouter.Handle("/achievements", Use(http.HandlerFunc(AchievementsHandler), PlayerNotFoundMiddleware, PRTMiddleware)).Methods(http.MethodGet)
+	PRTRouter.Handle("/export", Use(http.HandlerFunc(ExportHandler), PlayerNotFoundMiddleware, PRTMiddleware)).Methods(http.MethodGet)
+
+	// Any route under "/{platform}/{region}/{tag}/{mode}"
+	PRTMRouter := APIRouter.PathPrefix("/{platform}/{region}/{tag}/{mode}").Subrouter()
+	PRTMRouter.Handle("/all-hero-stats", Use(http.HandlerFunc(AllHeroStatsHandler), PlayerNotFoundMiddleware, GroupMiddleware, PRTMMiddleware)).Methods(http.MethodGet)
+	PRTMRouter.Handle("/heros-breakdown", Use(http.HandlerFunc(HerosHandler), PlayerNotFoundMiddleware, PRTMMiddleware)).Methods(http.MethodGet)
+	PRTMRouter.Handle("/roles", Use(http.HandlerFunc(RolesHandler), PlayerNotFoundMiddleware, PRTMMiddleware)).Methods(http.MethodGet)
+
+	// TODO: Hero name validation
+	PRTMRouter.Handle("/hero/{name}", Use(http.HandlerFunc(HeroHandler), PlayerNotFoundMiddleware, GroupMiddleware, PRTMMiddleware)).Methods(http.MethodGet)
+	PRTMRouter.Handle("/heroes", Use(http.HandlerFunc(AllHerosHandler), PlayerNotFoundMiddleware, GroupMiddleware, PRTMMiddleware)).Methods(http.MethodGet)
+}
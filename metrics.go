@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	UPSTREAM_PROFILE     = "profile"
+	UPSTREAM_SEARCH      = "search"
+	UPSTREAM_PATCH_NOTES = "patch_notes"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_http_requests_total",
+		Help: "Number of HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goverwatch_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests, by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	requestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "goverwatch_http_requests_in_flight",
+		Help: "Number of HTTP requests currently being handled.",
+	})
+
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_upstream_requests_total",
+		Help: "Number of requests made to the Overwatch site, by target.",
+	}, []string{"target"})
+
+	upstreamErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_upstream_errors_total",
+		Help: "Number of failed requests made to the Overwatch site, by target.",
+	}, []string{"target"})
+
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goverwatch_upstream_request_duration_seconds",
+		Help:    "Latency of requests made to the Overwatch site, by target.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"target"})
+
+	cacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_cache_lookups_total",
+		Help: "Number of responses of the Overwatch site looked up in the cache, by target and result (hit or miss).",
+	}, []string{"target", "result"})
+
+	cacheHitRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goverwatch_cache_hit_ratio",
+		Help: "Ratio of cache lookups that were hits since the process started, by target.",
+	}, []string{"target"})
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "goverwatch_cache_entries",
+		Help: "Number of responses of the Overwatch site cached in memory.",
+	}, func() float64 { return float64(upstreamCache.len()) })
+
+	parseMissingSectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_parse_missing_sections_total",
+		Help: "Number of times a section expected by a parser was missing from the career page, by parser and section.",
+	}, []string{"parser", "section"})
+
+	upstreamLayoutChangedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goverwatch_upstream_layout_changed_total",
+		Help: "Number of career pages a parser couldn't read at all because required sections were missing, by parser.",
+	}, []string{"parser"})
+)
+
+// statusRecorder is a http.ResponseWriter that remembers the status code written, so it can be reported once the
+// handler is done.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.status = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// MetricsMiddleware returns a middleware that records the number, latency and status of requests for each route.
+// The route is the path template (i.e. "/api/{platform}/{region}/{tag}/profile") rather than the actual path, to keep
+// the number of label values small. The router is used to find it, since the middleware wraps RecoveryMiddleware
+// (so requests that panic are recorded with the status of their error response) rather than being inside the router.
+func MetricsMiddleware(router *mux.Router) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := "unknown"
+			var match mux.RouteMatch
+			if router.Match(r, &match) && match.Route != nil {
+				if template, err := match.Route.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+
+			requestsInFlight.Inc()
+			defer requestsInFlight.Dec()
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(recorder, r)
+
+			requestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(recorder.status)).Inc()
+			requestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// observeUpstream records a single request made to the Overwatch site, on behalf of the request the context belongs to.
+// Called once the request has finished, with err being the error (if any) the request failed with.
+func observeUpstream(ctx context.Context, target string, start time.Time, err error) {
+	if info := requestInfoFromContext(ctx); info != nil {
+		atomic.AddInt64(&info.upstreamFetches, 1)
+	}
+
+	upstreamRequestsTotal.WithLabelValues(target).Inc()
+	upstreamDuration.WithLabelValues(target).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		upstreamErrorsTotal.WithLabelValues(target).Inc()
+	} else {
+		recordUpstreamSuccess(target)
+	}
+}
+
+// cacheStats counts the cache lookups of a single upstream target.
+type cacheStats struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// ratio returns the ratio of lookups that were hits, or 0 if there haven't been any.
+func (s *cacheStats) ratio() float64 {
+	hits, misses := s.hits.Load(), s.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// CACHE_STATS are the cache lookups of each upstream target since the process started.
+var CACHE_STATS = map[string]*cacheStats{
+	UPSTREAM_PROFILE:     {},
+	UPSTREAM_SEARCH:      {},
+	UPSTREAM_PATCH_NOTES: {},
+}
+
+// observeCache records a single cache lookup of a response of the Overwatch site, on behalf of the request the context
+// belongs to.
+func observeCache(ctx context.Context, target string, hit bool) {
+	info := requestInfoFromContext(ctx)
+
+	stats := CACHE_STATS[target]
+	if hit {
+		stats.hits.Add(1)
+		cacheLookupsTotal.WithLabelValues(target, "hit").Inc()
+		if info != nil {
+			atomic.AddInt64(&info.cacheHits, 1)
+		}
+	} else {
+		stats.misses.Add(1)
+		cacheLookupsTotal.WithLabelValues(target, "miss").Inc()
+		if info != nil {
+			atomic.AddInt64(&info.cacheMisses, 1)
+		}
+	}
+
+	cacheHitRatio.WithLabelValues(target).Set(stats.ratio())
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsRecordPanics(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) { panic("test panic") })
+
+	counter := requestsTotal.WithLabelValues("/panic", http.MethodGet, "500")
+	before := testutil.ToFloat64(counter)
+
+	handler := Use(router, RecoveryMiddleware, MetricsMiddleware(router))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("got %v requests recorded with HTTP 500, want 1", got)
+	}
+}
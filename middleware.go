@@ -2,11 +2,16 @@ package main
 
 import (
 	"net/http"
+	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"math"
+	"runtime/debug"
+	"strconv"
 )
 
 // Use is a basic middleware chainer.
 // This function allows an infinite amount of middleware to be called before the final handler ("h") is called.
+// Each middleware wraps the ones before it, so the last one is called first.
 func Use(h http.Handler, middleware ...func(http.Handler) http.Handler) http.Handler {
 	for _, m := range middleware {
 		h = m(h)
@@ -21,8 +26,7 @@ func PRTMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get the platform, region and tag from the request URL.
 		// Pack these into a new Player for future use.
-		vars := mux.Vars(r)
-		p := getPlayer(vars)
+		p := getPlayer(r)
 
 		errors := []string{}
 		if !p.platformIsValid() {
@@ -50,7 +54,7 @@ func PRTMMiddleware(h http.Handler) http.Handler {
 		// Get the platform, region and tag from the request URL.
 		// Pack these into a new Player for future use.
 		vars := mux.Vars(r)
-		p := getPlayer(vars)
+		p := getPlayer(r)
 
 		errors := []string{}
 		if !p.platformIsValid() {
@@ -75,23 +79,124 @@ func PRTMMiddleware(h http.Handler) http.Handler {
 	})
 }
 
+// FormatMiddleware is a validation middleware for ensuring that the output format asked for is a known one
+// (see getFormat). It is used by every API route, so a bad format is rejected before anything is fetched.
+// Since the format can come from the Accept header, every response says so in its Vary header, so a shared cache
+// doesn't send one format to a caller that asked for another.
+func FormatMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		if !formatIsValid(getFormat(r)) {
+			ReturnErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Errors: []string{ERROR_BAD_FORMAT}})
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// GroupMiddleware is a validation middleware for ensuring that the stats are grouped in a known way, if at all
+// (see getGroup). It is only used by the routes returning stats.
+func GroupMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !groupIsValid(getGroup(r)) {
+			ReturnErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Errors: []string{ERROR_BAD_GROUP}})
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
 // PlayerNotFoundMiddleware is a validation middleware for ensuring that the player actually exists.
 // The platform, region and tag combination is used to create a player. A helper method is called ("GetAccountByName")
 // to check that the combination is valid (returns at least 1 matching result).
-// If the check fails, a HTTP 404 error response is sent back indicating that player does not exist.
+// If the check fails, a HTTP 404 error response is sent back indicating that player does not exist. Otherwise the
+// results are kept in the request's context for GetMatchingAccount.
 func PlayerNotFoundMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get the platform, region and tag from the request URL.
 		// Pack these into a new Player for future use.
-		vars := mux.Vars(r)
-		p := getPlayer(vars)
+		p := getPlayer(r)
 
 		accounts := p.GetAccountByName()
 		if len(accounts) == 0 {
 			ReturnErrorResponse(w, r, http.StatusNotFound, ErrorResponse{Errors: []string{ERROR_PLAYER_NOT_FOUND}})
 			return
 		} else {
-			h.ServeHTTP(w, r)
+			h.ServeHTTP(w, r.WithContext(withAccounts(r.Context(), accounts)))
 		}
 	})
 }
+
+// RecoveryMiddleware recovers from any panic raised while handling the request, so the caller gets a HTTP 500 error
+// response instead of a dropped connection (or a HTTP 502 error response if the career page's layout changed, see
+// LayoutError, and a HTTP 503 error response if the Overwatch site is failing, see UpstreamUnavailableError).
+// The panic and its stack trace are logged along with the request ID, which is also included in the error response.
+func RecoveryMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+
+			// http.ErrAbortHandler is used to deliberately abort a response, so let net/http deal with it.
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
+			// The career page couldn't be parsed, since the Overwatch site changed its layout.
+			if layoutErr, ok := err.(*LayoutError); ok {
+				logger.Error("upstream layout changed",
+					"request_id", requestIDFromContext(r.Context()),
+					"parser", layoutErr.Parser,
+					"missing", layoutErr.Missing,
+				)
+
+				ReturnErrorResponse(w, r, http.StatusBadGateway, ErrorResponse{Errors: []string{ERROR_UPSTREAM_LAYOUT_CHANGED, layoutErr.Error()}})
+				return
+			}
+
+			// The Overwatch site kept failing, so its breaker is open (see circuitBreaker).
+			if unavailableErr, ok := err.(*UpstreamUnavailableError); ok {
+				logger.Warn("upstream unavailable",
+					"request_id", requestIDFromContext(r.Context()),
+					"target", unavailableErr.Target,
+				)
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(unavailableErr.RetryAfter.Seconds()))))
+				ReturnErrorResponse(w, r, http.StatusServiceUnavailable, ErrorResponse{Errors: []string{ERROR_UPSTREAM_UNAVAILABLE}})
+				return
+			}
+
+			logger.Error("panic",
+				"request_id", requestIDFromContext(r.Context()),
+				"error", err,
+				"stack", string(debug.Stack()),
+			)
+
+			ReturnErrorResponse(w, r, http.StatusInternalServerError, ErrorResponse{Errors: []string{ERROR_INTERNAL}})
+		}()
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// CORSMiddleware returns a middleware that applies the given CORS policy, so the API can be limited to known
+// front-ends.
+func CORSMiddleware(c CORSConfig) func(http.Handler) http.Handler {
+	options := []handlers.CORSOption{
+		handlers.AllowedOrigins(c.AllowedOrigins),
+		handlers.AllowedMethods(c.AllowedMethods),
+		handlers.AllowedHeaders(c.AllowedHeaders),
+		handlers.ExposedHeaders(c.ExposedHeaders),
+	}
+
+	if c.AllowCredentials {
+		options = append(options, handlers.AllowCredentials())
+	}
+
+	return handlers.CORS(options...)
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type routeDoc struct {
+	Summary string
+
+	// Response is a value of the type the route responds with, used to build the response schema.
+	// A nil Response means the route doesn't respond with JSON.
+	Response interface{}
+
+	// Errors are the status codes of the error responses the route can send (see ERROR_RESPONSES), which depend on the
+	// middleware it is registered with.
+	Errors []int
+}
+
+// ERROR_RESPONSES describes each status code of an error response.
+var ERROR_RESPONSES = map[int]string{
+	http.StatusBadRequest:            "Invalid request (i.e. an unknown platform, region, mode, format or group).",
+	http.StatusUnauthorized:          "Missing or invalid API key (X-API-Key header), if API keys are configured.",
+	http.StatusNotFound:              "Player (or hero) not found.",
+	http.StatusNotAcceptable:         "The csv and ndjson formats are only available for lists.",
+	http.StatusRequestEntityTooLarge: "The request body is too large.",
+	http.StatusUnprocessableEntity:   "The career page is missing sections every career page has, which are listed.",
+	http.StatusTooManyRequests:       "Too many requests from the client, see the Retry-After header.",
+	http.StatusInternalServerError:   "Something went wrong while handling the request.",
+	http.StatusBadGateway:            "The layout of the Overwatch site changed, so the career page couldn't be read.",
+	http.StatusServiceUnavailable:    "Not ready, or the Overwatch site is failing so requests to it are paused (see the Retry-After header).",
+}
+
+// The error responses of the routes registered by registerAPIRoutes, by the middleware they use.
+var (
+	// API_ERRORS are the error responses of every API route: RateLimitMiddleware, APIKeyMiddleware and FormatMiddleware.
+	API_ERRORS = []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusInternalServerError}
+
+	// PLAYER_ERRORS are the error responses of the routes of a player, which also use PlayerNotFoundMiddleware and
+	// fetch (and check the layout of) the player's career page.
+	PLAYER_ERRORS = withErrors(API_ERRORS, http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable)
+)
+
+// withErrors returns a copy of the error responses with more added.
+func withErrors(errors []int, more ...int) []int {
+	return append(slices.Clip(errors), more...)
+}
+
+// ROUTE_DOCS documents every route registered by newRouter, keyed by path template.
+// API routes are keyed without their "/api" or "/api/{API_VERSION}" prefix, since they are registered under both.
+// checkRouteDocs makes sure this stays in sync with the router.
+var ROUTE_DOCS = map[string]routeDoc{
+	"/":             {"Reports that the API is online.", "", nil},
+	"/metrics":      {"Prometheus metrics.", nil, nil},
+	"/healthz":      {"Reports that the process is alive.", map[string]string{}, nil},
+	"/readyz":       {"Reports whether the server is ready to handle requests.", map[string]string{}, []int{http.StatusServiceUnavailable}},
+	"/openapi.json": {"This OpenAPI document.", map[string]interface{}{}, nil},
+	"/graphql":      {"Executes a GraphQL query over player data.", map[string]interface{}{}, []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusRequestEntityTooLarge, http.StatusTooManyRequests, http.StatusInternalServerError}},
+	"/search/{tag}": {"Lists every platform, region and tag combination matching the tag.", []SearchResult{}, withErrors(API_ERRORS, http.StatusServiceUnavailable)},
+	"/status":       {"Reports whether each upstream is reachable.", Status{}, withErrors(API_ERRORS, http.StatusNotAcceptable)},
+	"/parse":        {"Parses a saved career page (the request body, as HTML) without fetching anything.", Export{}, withErrors(API_ERRORS, http.StatusNotAcceptable, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity)},
+
+	"/{platform}/{region}/{tag}/profile":      {"The player's profile overview.", Profile{}, withErrors(PLAYER_ERRORS, http.StatusNotAcceptable)},
+	"/{platform}/{region}/{tag}/achievements": {"Every achievement, and whether the player finished it.", []Achievement{}, PLAYER_ERRORS},
+	"/{platform}/{region}/{tag}/export":       {"Everything known about the player, in one document.", Export{}, withErrors(PLAYER_ERRORS, http.StatusNotAcceptable)},
+
+	"/{platform}/{region}/{tag}/{mode}/all-hero-stats":  {"The stats of all heroes combined.", []Stat{}, PLAYER_ERRORS},
+	"/{platform}/{region}/{tag}/{mode}/heros-breakdown": {"Each stat broken down by hero.", map[string][]HeroBreakdown{}, PLAYER_ERRORS},
+	"/{platform}/{region}/{tag}/{mode}/roles":           {"The player's stats aggregated by role.", []RoleSummary{}, PLAYER_ERRORS},
+	"/{platform}/{region}/{tag}/{mode}/hero/{name}":     {"The stats of a single hero.", []Stat{}, PLAYER_ERRORS},
+	"/{platform}/{region}/{tag}/{mode}/heroes":          {"The stats of every hero, keyed by hero name.", map[string][]Stat{}, PLAYER_ERRORS},
+}
+
+// GROUPED_RESPONSES documents the response of the stat routes when the caller asks for their stats grouped by section
+// ("?group=section"), keyed the same as ROUTE_DOCS.
+var GROUPED_RESPONSES = map[string]interface{}{
+	"/{platform}/{region}/{tag}/{mode}/all-hero-stats": []StatSection{},
+	"/{platform}/{region}/{tag}/{mode}/hero/{name}":    []StatSection{},
+	"/{platform}/{region}/{tag}/{mode}/heroes":         map[string][]StatSection{},
+}
+
+// routeDocKey returns the key of the route in ROUTE_DOCS.
+func routeDocKey(template string) string {
+	for _, prefix := range []string{"/api/" + API_VERSION, "/api"} {
+		if strings.HasPrefix(template, prefix+"/") {
+			return strings.TrimPrefix(template, prefix)
+		}
+	}
+	return template
+}
+
+type routeInfo struct {
+	template string
+	methods  []string
+}
+
+// getRoutes returns every route of the router that handles requests (as opposed to the path prefixes of subrouters).
+func getRoutes(router *mux.Router) []routeInfo {
+	routes := []routeInfo{}
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, _ := route.GetMethods()
+		routes = append(routes, routeInfo{template, methods})
+		return nil
+	})
+
+	return routes
+}
+
+// checkRouteDocs returns an error if any route registered on the router is missing from ROUTE_DOCS, or if ROUTE_DOCS
+// documents a route that isn't registered. Checked by TestRouteDocs and at startup, so the OpenAPI document can't
+// drift from the router.
+func checkRouteDocs(router *mux.Router) error {
+	used := map[string]bool{}
+	var problems []string
+
+	for _, route := range getRoutes(router) {
+		key := routeDocKey(route.template)
+		if _, ok := ROUTE_DOCS[key]; !ok {
+			problems = append(problems, "undocumented route "+route.template)
+		}
+		used[key] = true
+	}
+
+	for key := range ROUTE_DOCS {
+		if !used[key] {
+			problems = append(problems, "documented route "+key+" is not registered")
+		}
+	}
+
+	for key := range GROUPED_RESPONSES {
+		if !used[key] {
+			problems = append(problems, "grouped route "+key+" is not registered")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// OpenAPIHandler returns a handler serving the OpenAPI 3 document of every route registered on the router.
+// The document is built from the router (on the first request) rather than written by hand, so it always matches the
+// routes actually served.
+func OpenAPIHandler(router *mux.Router) http.Handler {
+	var once sync.Once
+	var spec map[string]interface{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			spec = buildOpenAPI(router)
+		})
+
+		// Always respond with JSON, regardless of the requested format.
+		response, err := json.Marshal(spec)
+		if err != nil {
+			panic(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+	})
+}
+
+// buildOpenAPI returns the OpenAPI 3 document describing every route registered on the router.
+func buildOpenAPI(router *mux.Router) map[string]interface{} {
+	schemas := map[string]interface{}{}
+	errorSchema := schemaFor(reflect.TypeOf(ErrorResponse{}), schemas)
+
+	paths := map[string]interface{}{}
+	for _, route := range getRoutes(router) {
+		doc := ROUTE_DOCS[routeDocKey(route.template)]
+		grouped, isGrouped := GROUPED_RESPONSES[routeDocKey(route.template)]
+
+		// Every API route but this document is registered by registerAPIRoutes, so it takes a format (see
+		// FormatMiddleware).
+		key := routeDocKey(route.template)
+		takesFormat := strings.HasPrefix(route.template, "/api/") && key != "/openapi.json"
+
+		// Routes that can find the career page's layout changed (or incomplete) parse one, so can send warnings about it.
+		parsesCareerPage := slices.Contains(doc.Errors, http.StatusBadGateway) || slices.Contains(doc.Errors, http.StatusUnprocessableEntity)
+
+		parameters := []interface{}{}
+		for _, name := range pathVariables(route.template) {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   parameterSchema(name),
+			})
+		}
+
+		if takesFormat {
+			parameters = append(parameters, map[string]interface{}{
+				"name":        "format",
+				"in":          "query",
+				"description": "Output format. csv and ndjson are only available for lists.",
+				"schema":      map[string]interface{}{"type": "string", "enum": sortedKeys(FORMATS)},
+			})
+		}
+
+		if isGrouped {
+			parameters = append(parameters, map[string]interface{}{
+				"name":        "group",
+				"in":          "query",
+				"description": "Group the stats by section (in the order they are shown on the career page), rather than a flat list.",
+				"schema":      map[string]interface{}{"type": "string", "enum": sortedKeys(GROUPS)},
+			})
+		}
+
+		ok := map[string]interface{}{"description": "OK"}
+		if doc.Response != nil {
+			schema := schemaFor(reflect.TypeOf(doc.Response), schemas)
+			if isGrouped {
+				schema = map[string]interface{}{
+					"oneOf": []interface{}{schema, schemaFor(reflect.TypeOf(grouped), schemas)},
+				}
+			}
+
+			ok["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+			}
+		} else {
+			ok["content"] = map[string]interface{}{"text/plain": map[string]interface{}{}}
+		}
+
+		responses := map[string]interface{}{"200": ok}
+		if parsesCareerPage {
+			ok["headers"] = map[string]interface{}{
+				PARSE_WARNINGS_HEADER: map[string]interface{}{
+					"description": "A section of the career page that was expected but missing, once per section.",
+					"schema":      map[string]interface{}{"type": "string"},
+				},
+			}
+		}
+
+		for _, code := range doc.Errors {
+			responses[strconv.Itoa(code)] = map[string]interface{}{
+				"description": ERROR_RESPONSES[code],
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": errorSchema},
+				},
+			}
+		}
+
+		operations := map[string]interface{}{}
+		for _, method := range route.methods {
+			operations[strings.ToLower(method)] = map[string]interface{}{
+				"summary":    doc.Summary,
+				"parameters": parameters,
+				"responses":  responses,
+			}
+		}
+
+		paths[route.template] = operations
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "goverwatch",
+			"version":     API_VERSION,
+			"description": "An (unofficial) REST API for Overwatch. \"/api\" is an alias of \"/api/" + API_VERSION + "\".",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// pathVariables returns the names of the variables in the path template, in order.
+func pathVariables(template string) []string {
+	names := []string{}
+	for _, part := range strings.Split(template, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			names = append(names, part[1:len(part)-1])
+		}
+	}
+	return names
+}
+
+// parameterSchema returns the schema of a path variable. Variables with a limited number of options get an enum.
+func parameterSchema(name string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+
+	switch name {
+	case "platform":
+		schema["enum"] = sortedKeys(PLATFORMS)
+	case "region":
+		schema["enum"] = sortedKeys(REGIONS)
+	case "mode":
+		schema["enum"] = sortedKeys(MODES)
+	}
+
+	return schema
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema of the given type, following its JSON encoding.
+// Named structs are added to schemas (the document's components) and referenced, so each is only described once.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaFor(t.Elem(), schemas)
+		if _, ok := schema["$ref"]; ok {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+
+		if _, ok := schemas[t.Name()]; !ok {
+			// Add a placeholder first, in case the struct refers to itself.
+			schemas[t.Name()] = map[string]interface{}{}
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	// interface{} and anything else can be any value.
+	return map[string]interface{}{}
+}
+
+// structSchema returns the object schema of the struct's exported, JSON encoded fields.
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		omitEmpty := false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				omitEmpty = omitEmpty || option == "omitempty"
+			}
+		}
+
+		properties[name] = schemaFor(field.Type, schemas)
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
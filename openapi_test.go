@@ -0,0 +1,105 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRouteDocs(t *testing.T) {
+	if err := checkRouteDocs(newRouter()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenAPIPaths(t *testing.T) {
+	router := newRouter()
+	paths := buildOpenAPI(router)["paths"].(map[string]interface{})
+
+	enums := map[string][]string{
+		"platform": sortedKeys(PLATFORMS),
+		"region":   sortedKeys(REGIONS),
+		"mode":     sortedKeys(MODES),
+		"format":   sortedKeys(FORMATS),
+		"group":    sortedKeys(GROUPS),
+	}
+
+	for _, route := range getRoutes(router) {
+		operations, ok := paths[route.template].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: missing from the OpenAPI paths", route.template)
+			continue
+		}
+
+		for _, method := range route.methods {
+			operation, ok := operations[strings.ToLower(method)].(map[string]interface{})
+			if !ok {
+				t.Errorf("%s %s: missing from the OpenAPI paths", method, route.template)
+				continue
+			}
+
+			parameters := map[string][]string{}
+			for _, parameter := range operation["parameters"].([]interface{}) {
+				parameter := parameter.(map[string]interface{})
+				schema := parameter["schema"].(map[string]interface{})
+				enum, _ := schema["enum"].([]string)
+				parameters[parameter["name"].(string)] = enum
+			}
+
+			expected := pathVariables(route.template)
+			if strings.HasPrefix(route.template, "/api/") && routeDocKey(route.template) != "/openapi.json" {
+				expected = append(expected, "format")
+			}
+			if _, ok := GROUPED_RESPONSES[routeDocKey(route.template)]; ok {
+				expected = append(expected, "group")
+			}
+
+			for _, name := range expected {
+				enum, ok := parameters[name]
+				if !ok {
+					t.Errorf("%s %s: missing parameter %q", method, route.template, name)
+					continue
+				}
+
+				if want, ok := enums[name]; ok && !slices.Equal(enum, want) {
+					t.Errorf("%s %s: parameter %q has enum %v, want %v", method, route.template, name, enum, want)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIErrorResponses(t *testing.T) {
+	paths := buildOpenAPI(newRouter())["paths"].(map[string]interface{})
+
+	tests := []struct {
+		path    string
+		method  string
+		want    []string
+		notWant []string
+	}{
+		{"/api/v1/openapi.json", "get", nil, []string{"400", "401", "404", "429"}},
+		{"/api/v1/status", "get", []string{"400", "401", "429"}, []string{"404", "502"}},
+		{"/api/v1/parse", "post", []string{"413", "422"}, []string{"404", "502", "503"}},
+		{"/api/v1/{platform}/{region}/{tag}/profile", "get", []string{"400", "401", "404", "429", "502", "503"}, nil},
+		{"/graphql", "post", []string{"401", "413", "429"}, []string{"404"}},
+		{"/healthz", "get", nil, []string{"500", "503"}},
+	}
+
+	for _, test := range tests {
+		operation := paths[test.path].(map[string]interface{})[test.method].(map[string]interface{})
+		responses := operation["responses"].(map[string]interface{})
+
+		for _, code := range test.want {
+			if _, ok := responses[code]; !ok {
+				t.Errorf("%s %s: missing response %s", test.method, test.path, code)
+			}
+		}
+
+		for _, code := range test.notWant {
+			if _, ok := responses[code]; ok {
+				t.Errorf("%s %s: got response %s, which the route can't send", test.method, test.path, code)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/http"
+)
+
+// MAX_PARSE_SIZE is the largest career page ParseHandler accepts. Career pages are usually well under 2 MB.
+const MAX_PARSE_SIZE = 10 << 20
+
+// ParseHandler parses a saved career page (the request body) with the same parsers as the player routes, and returns
+// everything found in it, in the same format as ExportHandler.
+// Nothing is fetched from the Overwatch site, which makes this useful for debugging the parsers against a page that
+// didn't parse as expected.
+func ParseHandler(w http.ResponseWriter, r *http.Request) {
+	d, err := ParseCareerPage(http.MaxBytesReader(w, r.Body, MAX_PARSE_SIZE))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ReturnErrorResponse(w, r, http.StatusRequestEntityTooLarge, ErrorResponse{Errors: []string{ERROR_PARSE_TOO_LARGE}})
+		} else {
+			ReturnErrorResponse(w, r, http.StatusBadRequest, ErrorResponse{Errors: []string{ERROR_BAD_HTML}})
+		}
+		return
+	}
+
+	// A saved page missing a required section was likely saved before it finished loading (or isn't a career page),
+	// which is the caller's mistake rather than a change of the Overwatch site's layout.
+	export, layoutErr := tryOfflineExport(r.Context(), d)
+	if layoutErr != nil {
+		errors := []string{ERROR_PARSE_INCOMPLETE}
+		for _, section := range layoutErr.Missing {
+			errors = append(errors, layoutErr.Parser+": missing "+section)
+		}
+
+		ReturnErrorResponse(w, r, http.StatusUnprocessableEntity, ErrorResponse{Errors: errors})
+		return
+	}
+
+	// Call helper function to marshal the export to JSON.
+	MarshalAndHandleErrors(w, r, export)
+}
+
+// tryOfflineExport returns GetOfflineExport, or the *LayoutError it raised if the page is missing a required section.
+// Any other panic is raised again.
+func tryOfflineExport(ctx context.Context, d *goquery.Document) (export Export, layoutErr *LayoutError) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if layoutErr, ok = r.(*LayoutError); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	return GetOfflineExport(ctx, d), nil
+}
+
+// ParseCareerPage returns the HTML document of a saved career page.
+func ParseCareerPage(r io.Reader) (*goquery.Document, error) {
+	return goquery.NewDocumentFromReader(r)
+}
+
+// GetOfflineExport returns everything found in a saved career page, the same as GetExport.
+// The player's actual level (and so their stars) comes from the search API rather than the career page, so it is
+// always 0, and the username is the one shown on the page.
+// Any warnings about the page's layout are added to the request the context belongs to.
+func GetOfflineExport(ctx context.Context, d *goquery.Document) Export {
+	return GetExport(d, &Player{ctx: ctx}, Account{})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type PatchNote struct {
+	Version     string `json:"version"`
+	BuildNumber int    `json:"build_number"`
+	Published   string `json:"published"`
+	Slug        string `json:"slug"`
+}
+
+// GetPatchNotes returns the latest patch notes, newest first.
+// Panics if the patch notes can't be fetched or decoded, same as GetProfileDoc.
+func GetPatchNotes(ctx context.Context) []PatchNote {
+	body := fetchUpstream(ctx, UPSTREAM_PATCH_NOTES, config.Upstream.PatchNoteURL)
+
+	// The patch note list from the Battle.net CMS. Publish is a Unix timestamp in milliseconds.
+	var list struct {
+		PatchNotes []struct {
+			PatchVersion string `json:"patchVersion"`
+			BuildNumber  int    `json:"buildNumber"`
+			Publish      int64  `json:"publish"`
+			Slug         string `json:"slug"`
+		} `json:"patchNotes"`
+	}
+
+	if err := json.Unmarshal(body, &list); err != nil {
+		panic(err)
+	}
+
+	notes := []PatchNote{}
+	for _, n := range list.PatchNotes {
+		notes = append(notes, PatchNote{
+			Version:     n.PatchVersion,
+			BuildNumber: n.BuildNumber,
+			Published:   time.UnixMilli(n.Publish).UTC().Format(time.RFC3339),
+			Slug:        n.Slug,
+		})
+	}
+
+	return notes
+}
@@ -1,21 +1,38 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"strings"
 	"github.com/PuerkitoBio/goquery"
-	"log"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/url"
 )
 
 type Player struct {
 	Platform string
 	Region   string
 	Tag      string
+
+	// ctx is the context of the request the player was created for.
+	// Requests to the Overwatch site are made (and counted) within this context.
+	ctx context.Context
 }
 
-// getPlayer returns a player object from a map of vars.
+// getPlayer returns a player object from the vars of the request.
 // Used in routes that contain "/{platform}/{region}/{tag}"
-func getPlayer(vars map[string]string) *Player {
-	return &Player{strings.ToLower(vars["platform"]), strings.ToLower(vars["region"]), vars["tag"]}
+func getPlayer(r *http.Request) *Player {
+	vars := mux.Vars(r)
+	return &Player{strings.ToLower(vars["platform"]), strings.ToLower(vars["region"]), vars["tag"], r.Context()}
+}
+
+// context returns the context of the request the player was created for, or an empty context if there is none.
+func (p *Player) context() context.Context {
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
 }
 
 func (p *Player) platformIsValid() bool {
@@ -34,12 +51,26 @@ func heroIsValid(hero string) bool {
 	return HEROS[strings.ToLower(hero)]
 }
 
+// upstreamClient returns the client used for requests to the Overwatch site.
+func upstreamClient() *http.Client {
+	return &http.Client{Timeout: config.Upstream.Timeout}
+}
+
 // GetProfileDoc gets the matching player's HTML document.
-// TODO: Better error handling
+// Panics if the document can't be fetched or parsed, which RecoveryMiddleware turns into a HTTP 500 error response.
 func (p *Player) GetProfileDoc() *goquery.Document {
-	d, err := goquery.NewDocument(p.formatProfileURL())
+	profileURL := p.formatProfileURL()
+	body := fetchUpstream(p.context(), UPSTREAM_PROFILE, profileURL)
+
+	d, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		panic(err)
+	}
+
+	// The URL tells a fetched document apart from a saved one (see ParseCareerPage).
+	d.Url, err = url.Parse(profileURL)
 	if err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 
 	return d
@@ -53,16 +84,16 @@ func (p *Player) formatProfileURL() string {
 	// PC: https://playoverwatch.com/en-us/career/${platform}/${region}/${tag}
 
 	if p.Platform == "pc" {
-		return BASE_URL + p.Platform + "/" + p.Region + "/" + p.sanitizeBattleTag()
+		return config.Upstream.BaseURL + p.Platform + "/" + p.Region + "/" + p.sanitizeBattleTag()
 	} else {
-		return BASE_URL + p.Platform + "/" + p.sanitizeBattleTag()
+		return config.Upstream.BaseURL + p.Platform + "/" + p.sanitizeBattleTag()
 	}
 
 }
 
 // formatSearchURL constructs and returns the search URL for the given tag.
 func (p *Player) formatSearchURL() string {
-	return SEARCH_URL + p.sanitizeBattleTag()
+	return config.Upstream.SearchURL + p.sanitizeBattleTag()
 }
 
 // sanitizeBattleTag returns a sanitized BattleTag.
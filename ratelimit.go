@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RATE_LIMIT_WINDOW is the window requests are counted in (see RateLimitConfig).
+const RATE_LIMIT_WINDOW = time.Minute
+
+// rateLimiter counts the requests of each client (by IP address) in the current window.
+// The counts are all reset at the start of each window, so only the clients of the current window are kept.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	window time.Time
+	counts map[string]int
+}
+
+var clientRateLimiter = &rateLimiter{counts: map[string]int{}}
+
+// allow counts a request of the client, and returns whether it is within the limit. If it isn't, the time left until
+// the next window is returned as well.
+func (l *rateLimiter) allow(client string, limit int, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if window := now.Truncate(RATE_LIMIT_WINDOW); !window.Equal(l.window) {
+		l.window = window
+		l.counts = map[string]int{}
+	}
+
+	l.counts[client]++
+	if l.counts[client] > limit {
+		return false, l.window.Add(RATE_LIMIT_WINDOW).Sub(now)
+	}
+
+	return true, 0
+}
+
+// clientIP returns the IP address of the given remote address ("host:port"), or the address itself if it has no port.
+// Proxy headers (i.e. X-Forwarded-For) aren't trusted, since any client can send them.
+func clientIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// RateLimitMiddleware limits each client to config.RateLimit.RequestsPerMinute requests, responding with a HTTP 429
+// error (and a Retry-After header) to any request over the limit. Nothing is limited if the limit is 0.
+func RateLimitMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := config.RateLimit.RequestsPerMinute
+		if limit == 0 {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if ok, retryAfter := clientRateLimiter.allow(clientIP(r.RemoteAddr), limit, time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ReturnErrorResponse(w, r, http.StatusTooManyRequests, ErrorResponse{Errors: []string{ERROR_RATE_LIMITED}})
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// grpcRateLimitInterceptor is the gRPC equivalent of RateLimitMiddleware, sharing its limits. Calls over the limit
+// fail with ResourceExhausted.
+func grpcRateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	limit := config.RateLimit.RequestsPerMinute
+	if limit == 0 {
+		return handler(ctx, req)
+	}
+
+	client := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		client = clientIP(p.Addr.String())
+	}
+
+	if ok, _ := clientRateLimiter.allow(client, limit, time.Now()); !ok {
+		return nil, status.Error(codes.ResourceExhausted, ERROR_RATE_LIMITED)
+	}
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+)
+
+type RoleSummary struct {
+	Role            string             `json:"role"`
+	Heroes          []string           `json:"heroes"`
+	TimePlayed      float64            `json:"time_played"`
+	TimePlayedShare float64            `json:"time_played_share"`
+	GamesWon        int                `json:"games_won"`
+	GamesPlayed     int                `json:"games_played"`
+	WinRate         float64            `json:"win_rate"`
+	Combat          map[string]float64 `json:"combat"`
+}
+
+// RolesHandler aggregates the player's hero stats by role (tank, damage, support) and returns a JSON array with one
+// summary per role.
+// Each summary contains the time played (in seconds) and its share of the total, the win rate and the combat totals
+// (keyed by stat key, see NormalizeStat) of every hero in that role.
+func RolesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get the platform, region and tag from the request URL.
+	// Pack these into a new Player for future use.
+	vars := mux.Vars(r)
+	p := getPlayer(r)
+
+	// Get mode from request URL.
+	mode := strings.ToLower(vars["mode"])
+
+	d := p.GetProfileDoc()
+
+	// Call helper function to make sure the career page has the sections the parsers need.
+	CheckLayout(p.context(), d, PARSER_HEROS_BREAKDOWN, mode)
+	CheckLayout(p.context(), d, PARSER_HERO_STATS, mode)
+
+	// Call helper function to aggregate the stats by role.
+	roles := GetRoleSummaries(d, mode)
+
+	// Call helper function to marshal the slice to JSON.
+	MarshalAndHandleErrors(w, r, roles)
+}
+
+// GetRoleSummaries returns a summary for each role in ROLES, aggregated from the hero breakdowns and the per-hero stats
+// of the given mode.
+func GetRoleSummaries(d *goquery.Document, mode string) []RoleSummary {
+	summaries := map[string]*RoleSummary{}
+	for _, role := range ROLES {
+		summaries[role] = &RoleSummary{Role: role, Heroes: []string{}, Combat: map[string]float64{}}
+	}
+
+	// The "Time Played" breakdown lists every hero the player has played, along with how long they played them.
+	totalTime := 0.0
+	for _, breakdown := range GetHeroBreakdowns(d, mode)["Time Played"] {
+		seconds := TrimToSeconds(breakdown.Value)
+		totalTime += seconds
+
+		if role, ok := HERO_ROLES[strings.ToLower(breakdown.Hero)]; ok {
+			summaries[role].TimePlayed += seconds
+		}
+	}
+
+	// Iterate over every hero on the career page (in the order they are listed, so the heroes of each role are always in
+	// the same order) and add their stats to their role.
+	heroHexMap := GetHeroHexMap(d)
+	for _, hero := range GetHeroNames(d) {
+		hex := heroHexMap[hero]
+		role, ok := HERO_ROLES[hero]
+		if !ok {
+			// Skip "all heroes" and any hero we don't know the role of yet.
+			continue
+		}
+
+		stats := GetHeroStats(d, mode, hex)
+		if len(stats) == 0 {
+			continue
+		}
+
+		summary := summaries[role]
+		summary.Heroes = append(summary.Heroes, hero)
+
+		for _, stat := range stats {
+			switch {
+			case stat.Key == "games_won":
+				summary.GamesWon += TrimToInt(stat.Value)
+			case stat.Key == "games_played":
+				summary.GamesPlayed += TrimToInt(stat.Value)
+			case stat.SectionName == "Combat":
+				// Durations (i.e. "Time Spent on Fire") and percentages can't be summed, so skip them.
+				if strings.ContainsAny(stat.Value, ":%") {
+					continue
+				}
+				// The totals are keyed by the stat's key, since its name depends on the value (i.e. "Elimination(s)").
+				summary.Combat[stat.Key] += TrimToFloat(stat.Value)
+			}
+		}
+	}
+
+	roles := []RoleSummary{}
+	for _, role := range ROLES {
+		summary := summaries[role]
+
+		if totalTime > 0 {
+			summary.TimePlayedShare = summary.TimePlayed / totalTime * 100
+		}
+
+		if summary.GamesPlayed > 0 {
+			summary.WinRate = float64(summary.GamesWon) / float64(summary.GamesPlayed) * 100
+		}
+
+		roles = append(roles, *summary)
+	}
+
+	return roles
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testRolesPage is a career page with two support heroes, one with a single elimination ("Elimination") and one with
+// several ("Eliminations").
+const testRolesPage = `<html><body><div><div class="profile-background">
+<select data-group-id="stats">
+<option option-id="Mercy" value="0x02E0000000000004">Mercy</option>
+<option option-id="Ana" value="0x02E000000000013B">Ana</option>
+</select>
+<div id="quickplay"><div class="career-stats-section"><div>
+<div class="row" data-category-id="0x02E0000000000004"><div class="card-stat-block"><table>
+<thead><tr><th><h5 class="stat-title">Combat</h5></th></tr></thead>
+<tbody><tr><td>Eliminations</td><td>3</td></tr></tbody>
+</table></div></div>
+<div class="row" data-category-id="0x02E000000000013B"><div class="card-stat-block"><table>
+<thead><tr><th><h5 class="stat-title">Combat</h5></th></tr></thead>
+<tbody><tr><td>Elimination</td><td>1</td></tr></tbody>
+</table></div></div>
+</div></div></div>
+</div></div></body></html>`
+
+func TestGetRoleSummaries(t *testing.T) {
+	d, err := goquery.NewDocumentFromReader(strings.NewReader(testRolesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, summary := range GetRoleSummaries(d, "quickplay") {
+		if summary.Role != ROLE_SUPPORT {
+			continue
+		}
+		found = true
+
+		// The heroes are in the order they are listed on the page.
+		if want := []string{"mercy", "ana"}; !slices.Equal(summary.Heroes, want) {
+			t.Errorf("got heroes %v, want %v", summary.Heroes, want)
+		}
+
+		// "Elimination" and "Eliminations" are the same stat.
+		if len(summary.Combat) != 1 || summary.Combat["eliminations"] != 4 {
+			t.Errorf("got combat totals %v, want eliminations 4", summary.Combat)
+		}
+	}
+
+	if !found {
+		t.Errorf("got no %s summary", ROLE_SUPPORT)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"github.com/andybalholm/cascadia"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// Selectors is the schema of every CSS selector (and the attribute and class names) used to parse the career page.
+// The built-in defaults can be overridden under "selectors" in the config file, so a change to the markup of the
+// Overwatch site can be fixed by editing the config file and reloading it (see reloadOnSIGHUP), without a rebuild.
+//
+// Selectors may contain placeholders, which are filled in when used:
+// "{mode}" is the mode being parsed, "{category}" the data-category-id of a hero or stat and "{stat}" the label of a
+// stat. Fields ending in Attr, Class or Label are not selectors.
+type Selectors struct {
+	// ModeSection and CareerStatsSection are the sections of a mode, and of the mode's career stats.
+	ModeSection        string `yaml:"mode_section"`
+	CareerStatsSection string `yaml:"career_stats_section"`
+
+	Profile        ProfileSelectors        `yaml:"profile"`
+	Achievements   AchievementSelectors    `yaml:"achievements"`
+	HeroList       OptionSelectors         `yaml:"hero_list"`
+	StatList       OptionSelectors         `yaml:"stat_list"`
+	Stats          StatSelectors           `yaml:"stats"`
+	HeroComparison HeroComparisonSelectors `yaml:"hero_comparison"`
+}
+
+type ProfileSelectors struct {
+	Username string `yaml:"username"`
+	Avatar   string `yaml:"avatar"`
+	Level    string `yaml:"level"`
+
+	// LevelStars are the stars shown below the level (if the player has any).
+	LevelStars string `yaml:"level_stars"`
+
+	// ModeStat is the label cell of a stat in the summary of a mode. The value is in the cell after it.
+	ModeStat         string `yaml:"mode_stat"`
+	GamesWonLabel    string `yaml:"games_won_label"`
+	GamesPlayedLabel string `yaml:"games_played_label"`
+	TimePlayedLabel  string `yaml:"time_played_label"`
+
+	CompetitiveRank      string `yaml:"competitive_rank"`
+	CompetitiveRankValue string `yaml:"competitive_rank_value"`
+	CompetitiveRankImage string `yaml:"competitive_rank_image"`
+
+	// CompetitiveRoleRanks are the ranks of each role, if the player played role queue. The rest are relative to a
+	// role rank. The role is only named in the CompetitiveRoleAttr attribute (i.e. "Tank Skill Rating") of
+	// CompetitiveRoleRankRole.
+	CompetitiveRoleRanks     string `yaml:"competitive_role_ranks"`
+	CompetitiveRoleRankRole  string `yaml:"competitive_role_rank_role"`
+	CompetitiveRoleAttr      string `yaml:"competitive_role_attr"`
+	CompetitiveRoleRankValue string `yaml:"competitive_role_rank_value"`
+	CompetitiveRoleRankImage string `yaml:"competitive_role_rank_image"`
+}
+
+type AchievementSelectors struct {
+	Section string `yaml:"section"`
+
+	// Cards are the achievements. The rest of the selectors are relative to a card.
+	Cards         string `yaml:"cards"`
+	Image         string `yaml:"image"`
+	Title         string `yaml:"title"`
+	FinishedClass string `yaml:"finished_class"`
+
+	// The description is in a sibling of the card, with the ID found in TooltipAttr.
+	TooltipAttr string `yaml:"tooltip_attr"`
+	Description string `yaml:"description"`
+}
+
+// OptionSelectors is a <select> element, where each option is a name and a value.
+type OptionSelectors struct {
+	Options   string `yaml:"options"`
+	NameAttr  string `yaml:"name_attr"`
+	ValueAttr string `yaml:"value_attr"`
+}
+
+type StatSelectors struct {
+	// The children of Cards are the stat cards. The rest of the selectors are relative to a card.
+	Cards string `yaml:"cards"`
+	Title string `yaml:"title"`
+
+	// Rows are the stats of a card. Name and Value are relative to a row.
+	Rows  string `yaml:"rows"`
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type HeroComparisonSelectors struct {
+	Section string `yaml:"section"`
+
+	// The children of Bars (relative to Section) are the bars of each hero. The rest are relative to a bar.
+	Bars        string `yaml:"bars"`
+	PercentAttr string `yaml:"percent_attr"`
+	Image       string `yaml:"image"`
+	Hero        string `yaml:"hero"`
+	Value       string `yaml:"value"`
+}
+
+// defaultSelectors returns the selectors matching the markup of the Overwatch site.
+func defaultSelectors() Selectors {
+	return Selectors{
+		ModeSection:        "#{mode}",
+		CareerStatsSection: "#{mode} .career-stats-section",
+
+		Profile: ProfileSelectors{
+			Username: ".header-masthead",
+			Avatar:   ".player-portrait",
+			Level:    ".player-level",
+
+			LevelStars: ".player-level .player-rank",
+
+			ModeStat:         "#{mode} td:contains('{stat}')",
+			GamesWonLabel:    "Games Won",
+			GamesPlayedLabel: "Games Played",
+			TimePlayedLabel:  "Time Played",
+
+			CompetitiveRank:      ".competitive-rank",
+			CompetitiveRankValue: ".competitive-rank div",
+			CompetitiveRankImage: ".competitive-rank img",
+
+			CompetitiveRoleRanks:     ".competitive-rank .competitive-rank-role",
+			CompetitiveRoleRankRole:  ".competitive-rank-tier",
+			CompetitiveRoleAttr:      "data-ow-tooltip-text",
+			CompetitiveRoleRankValue: ".competitive-rank-level",
+			CompetitiveRoleRankImage: ".competitive-rank-tier-icon",
+		},
+		Achievements: AchievementSelectors{
+			Section:       "#achievements-section",
+			Cards:         "#achievements-section .toggle-display .media-card",
+			Image:         "img",
+			Title:         ".media-card-caption > .media-card-title",
+			FinishedClass: "m-disabled",
+			TooltipAttr:   "data-tooltip",
+			Description:   "p",
+		},
+		HeroList: OptionSelectors{
+			Options:   "select[data-group-id='stats'] > option",
+			NameAttr:  "option-id",
+			ValueAttr: "value",
+		},
+		StatList: OptionSelectors{
+			Options:   "select[data-group-id='comparisons'] > option",
+			NameAttr:  "option-id",
+			ValueAttr: "value",
+		},
+		Stats: StatSelectors{
+			Cards: "body > div > .profile-background > #{mode} > .career-stats-section > div > .row[data-category-id='{category}']",
+			Title: ".card-stat-block > table > thead > tr > th .stat-title",
+			Rows:  ".card-stat-block table > tbody > tr",
+			Name:  "td:nth-child(1)",
+			Value: "td:nth-child(2)",
+		},
+		HeroComparison: HeroComparisonSelectors{
+			Section:     "body > div > .profile-background > #{mode} > .hero-comparison-section .row.column",
+			Bars:        "div[data-category-id='{category}']",
+			PercentAttr: "data-overwatch-progress-percent",
+			Image:       "img",
+			Hero:        ".bar-container .bar-text .title",
+			Value:       ".bar-container .bar-text .description",
+		},
+	}
+}
+
+// selectors are the selectors the service is running with. They are replaced at startup and on every reload.
+var selectors atomic.Pointer[Selectors]
+
+func init() {
+	s := defaultSelectors()
+	selectors.Store(&s)
+}
+
+// getSelectors returns the current selectors. Parsers should call it once, so they use the same selectors throughout
+// even if they are reloaded in the meantime.
+func getSelectors() *Selectors {
+	return selectors.Load()
+}
+
+// setSelectors replaces the current selectors.
+func setSelectors(s Selectors) {
+	selectors.Store(&s)
+}
+
+// fill returns the selector with its placeholders replaced, given as pairs of placeholders and values
+// (i.e. fill(s, "{mode}", mode)).
+func fill(selector string, placeholders ...string) string {
+	return strings.NewReplacer(placeholders...).Replace(selector)
+}
+
+// validate returns an error for each selector that is empty or isn't a valid CSS selector.
+func (s *Selectors) validate() []error {
+	var errs []error
+	s.walk(reflect.ValueOf(*s), "selectors", func(path string, fieldName string, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("config: %s must be set", path))
+			return
+		}
+
+		for _, suffix := range []string{"Attr", "Class", "Label"} {
+			if strings.HasSuffix(fieldName, suffix) {
+				return
+			}
+		}
+
+		// Fill in the placeholders with example values, since they aren't valid CSS.
+		example := fill(value, "{mode}", "quickplay", "{category}", "0x0", "{stat}", "Games Won")
+		if _, err := cascadia.Compile(example); err != nil {
+			errs = append(errs, fmt.Errorf("config: %s is not a valid selector: %v", path, err))
+		}
+	})
+	return errs
+}
+
+// walk calls f with the path (as in the config file), field name and value of every string field of v.
+func (s *Selectors) walk(v reflect.Value, path string, f func(path string, fieldName string, value string)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fieldPath := path + "." + field.Tag.Get("yaml")
+
+		switch v.Field(i).Kind() {
+		case reflect.Struct:
+			s.walk(v.Field(i), fieldPath, f)
+		case reflect.String:
+			f(fieldPath, field.Name, v.Field(i).String())
+		}
+	}
+}
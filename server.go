@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// serve runs the server until it receives SIGINT or SIGTERM, and then shuts it down gracefully.
+// While shutting down, the server is reported as not ready and stops accepting new connections, while in-flight
+// requests are given up to serverConfig.ShutdownTimeout to finish.
+// If serverConfig.GRPCAddress is set, the gRPC service is run on that address alongside the HTTP server, and is
+// stopped along with it.
+func serve(handler http.Handler, serverConfig ServerConfig) error {
+	server := &http.Server{
+		Addr:         serverConfig.Address,
+		Handler:      handler,
+		ReadTimeout:  serverConfig.ReadTimeout,
+		WriteTimeout: serverConfig.WriteTimeout,
+		IdleTimeout:  serverConfig.IdleTimeout,
+	}
+
+	errs := make(chan error, 2)
+
+	var grpcServer *grpc.Server
+	if serverConfig.GRPCAddress != "" {
+		listener, err := net.Listen("tcp", serverConfig.GRPCAddress)
+		if err != nil {
+			return err
+		}
+
+		grpcServer = newGRPCServer()
+
+		logger.Info("listening (grpc)", "address", serverConfig.GRPCAddress)
+		go func() {
+			errs <- grpcServer.Serve(listener)
+		}()
+	}
+
+	go func() {
+		if serverConfig.TLSCertFile != "" {
+			errs <- server.ListenAndServeTLS(serverConfig.TLSCertFile, serverConfig.TLSKeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-errs:
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down", "timeout", serverConfig.ShutdownTimeout.String())
+	ready.Store(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+
+	// The gRPC server is stopped alongside the HTTP server, within the same timeout.
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			stopGRPC(shutdownCtx, grpcServer)
+			close(stopped)
+		}()
+		defer func() { <-stopped }()
+	}
+
+	return server.Shutdown(shutdownCtx)
+}
+
+// stopGRPC stops the gRPC server gracefully, giving in-flight calls until the context is done to finish before they
+// are cancelled.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
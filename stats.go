@@ -0,0 +1,156 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// statDefinition is a stat shown on the career page. Key is its stable, machine-readable name and Name its canonical
+// name. Most stats are labelled in the singular if their value is 1 and in the plural otherwise (i.e. "Elimination"
+// and "Eliminations"), so any label other than Name is listed in Aliases.
+type statDefinition struct {
+	Key     string
+	Name    string
+	Aliases []string
+}
+
+// STAT_DEFINITIONS are the stats of the career page that have a key and canonical name, roughly in the order they are
+// shown. Keys must never change once added, since clients look stats up by them.
+var STAT_DEFINITIONS = []statDefinition{
+	// Combat
+	{"all_damage_done", "All Damage Done", nil},
+	{"barrier_damage_done", "Barrier Damage Done", nil},
+	{"critical_hits", "Critical Hits", []string{"Critical Hit"}},
+	{"critical_hit_accuracy", "Critical Hit Accuracy", nil},
+	{"deaths", "Deaths", []string{"Death"}},
+	{"eliminations", "Eliminations", []string{"Elimination"}},
+	{"environmental_deaths", "Environmental Deaths", []string{"Environmental Death"}},
+	{"environmental_kills", "Environmental Kills", []string{"Environmental Kill"}},
+	{"final_blows", "Final Blows", []string{"Final Blow"}},
+	{"hero_damage_done", "Hero Damage Done", nil},
+	{"melee_final_blows", "Melee Final Blows", []string{"Melee Final Blow"}},
+	{"multikills", "Multikills", []string{"Multikill"}},
+	{"objective_kills", "Objective Kills", []string{"Objective Kill"}},
+	{"objective_time", "Objective Time", nil},
+	{"quick_melee_accuracy", "Quick Melee Accuracy", nil},
+	{"solo_kills", "Solo Kills", []string{"Solo Kill"}},
+	{"time_spent_on_fire", "Time Spent on Fire", nil},
+	{"weapon_accuracy", "Weapon Accuracy", nil},
+
+	// Assists
+	{"defensive_assists", "Defensive Assists", []string{"Defensive Assist"}},
+	{"healing_done", "Healing Done", nil},
+	{"offensive_assists", "Offensive Assists", []string{"Offensive Assist"}},
+	{"recon_assists", "Recon Assists", []string{"Recon Assist"}},
+	{"shield_generators_destroyed", "Shield Generators Destroyed", []string{"Shield Generator Destroyed"}},
+	{"teleporter_pads_destroyed", "Teleporter Pads Destroyed", []string{"Teleporter Pad Destroyed"}},
+	{"turrets_destroyed", "Turrets Destroyed", []string{"Turret Destroyed"}},
+
+	// Best
+	{"all_damage_done_most_in_game", "All Damage Done - Most in Game", nil},
+	{"barrier_damage_done_most_in_game", "Barrier Damage Done - Most in Game", nil},
+	{"critical_hits_most_in_game", "Critical Hits - Most in Game", []string{"Critical Hit - Most in Game"}},
+	{"defensive_assists_most_in_game", "Defensive Assists - Most in Game", []string{"Defensive Assist - Most in Game"}},
+	{"eliminations_most_in_game", "Eliminations - Most in Game", []string{"Elimination - Most in Game"}},
+	{"environmental_kills_most_in_game", "Environmental Kills - Most in Game", []string{"Environmental Kill - Most in Game"}},
+	{"final_blows_most_in_game", "Final Blows - Most in Game", []string{"Final Blow - Most in Game"}},
+	{"healing_done_most_in_game", "Healing Done - Most in Game", nil},
+	{"hero_damage_done_most_in_game", "Hero Damage Done - Most in Game", nil},
+	{"kill_streak_best", "Kill Streak - Best", nil},
+	{"melee_final_blows_most_in_game", "Melee Final Blows - Most in Game", []string{"Melee Final Blow - Most in Game"}},
+	{"multikill_best", "Multikill - Best", nil},
+	{"objective_kills_most_in_game", "Objective Kills - Most in Game", []string{"Objective Kill - Most in Game"}},
+	{"objective_time_most_in_game", "Objective Time - Most in Game", nil},
+	{"offensive_assists_most_in_game", "Offensive Assists - Most in Game", []string{"Offensive Assist - Most in Game"}},
+	{"solo_kills_most_in_game", "Solo Kills - Most in Game", []string{"Solo Kill - Most in Game"}},
+	{"time_spent_on_fire_most_in_game", "Time Spent on Fire - Most in Game", nil},
+	{"weapon_accuracy_best_in_game", "Weapon Accuracy - Best in Game", nil},
+
+	// Average
+	{"all_damage_done_avg_per_10_min", "All Damage Done - Avg per 10 Min", nil},
+	{"barrier_damage_done_avg_per_10_min", "Barrier Damage Done - Avg per 10 Min", nil},
+	{"critical_hits_avg_per_10_min", "Critical Hits - Avg per 10 Min", []string{"Critical Hit - Avg per 10 Min"}},
+	{"deaths_avg_per_10_min", "Deaths - Avg per 10 Min", []string{"Death - Avg per 10 Min"}},
+	{"eliminations_avg_per_10_min", "Eliminations - Avg per 10 Min", []string{"Elimination - Avg per 10 Min"}},
+	{"final_blows_avg_per_10_min", "Final Blows - Avg per 10 Min", []string{"Final Blow - Avg per 10 Min"}},
+	{"healing_done_avg_per_10_min", "Healing Done - Avg per 10 Min", nil},
+	{"hero_damage_done_avg_per_10_min", "Hero Damage Done - Avg per 10 Min", nil},
+	{"objective_kills_avg_per_10_min", "Objective Kills - Avg per 10 Min", []string{"Objective Kill - Avg per 10 Min"}},
+	{"objective_time_avg_per_10_min", "Objective Time - Avg per 10 Min", nil},
+	{"solo_kills_avg_per_10_min", "Solo Kills - Avg per 10 Min", []string{"Solo Kill - Avg per 10 Min"}},
+	{"time_spent_on_fire_avg_per_10_min", "Time Spent on Fire - Avg per 10 Min", nil},
+
+	// Game
+	{"games_lost", "Games Lost", []string{"Game Lost"}},
+	{"games_played", "Games Played", []string{"Game Played"}},
+	{"games_tied", "Games Tied", []string{"Game Tied"}},
+	{"games_won", "Games Won", []string{"Game Won"}},
+	{"time_played", "Time Played", nil},
+
+	// Match Awards
+	{"cards", "Cards", []string{"Card"}},
+	{"medals", "Medals", []string{"Medal"}},
+	{"medals_bronze", "Medals - Bronze", []string{"Medal - Bronze"}},
+	{"medals_gold", "Medals - Gold", []string{"Medal - Gold"}},
+	{"medals_silver", "Medals - Silver", []string{"Medal - Silver"}},
+}
+
+// statDefinitionsByLabel indexes STAT_DEFINITIONS by (lowercase) label, both the name and the aliases.
+var statDefinitionsByLabel = indexStatDefinitions(STAT_DEFINITIONS)
+
+func indexStatDefinitions(definitions []statDefinition) map[string]statDefinition {
+	index := map[string]statDefinition{}
+	for _, definition := range definitions {
+		index[strings.ToLower(definition.Name)] = definition
+		for _, alias := range definition.Aliases {
+			index[strings.ToLower(alias)] = definition
+		}
+	}
+	return index
+}
+
+// MAX_UNKNOWN_STAT_LABELS is the most labels unknownStatLabels remembers. Once it is full, further unknown labels
+// aren't logged until it is reset, since there is no telling how many distinct labels the career pages have.
+const MAX_UNKNOWN_STAT_LABELS = 1000
+
+// unknownStatLabels are the labels missing from STAT_DEFINITIONS that have been logged, so each is only logged once.
+// It is reset every time the config is reloaded (see reloadOnSIGHUP).
+var unknownStatLabels = struct {
+	sync.Mutex
+	labels map[string]bool
+}{labels: map[string]bool{}}
+
+// logUnknownStatLabel logs a label missing from STAT_DEFINITIONS, so it can be added to the table.
+// Each label is only logged once, and nothing is logged once MAX_UNKNOWN_STAT_LABELS labels have been.
+func logUnknownStatLabel(label string) {
+	unknownStatLabels.Lock()
+	defer unknownStatLabels.Unlock()
+
+	if unknownStatLabels.labels[label] || len(unknownStatLabels.labels) >= MAX_UNKNOWN_STAT_LABELS {
+		return
+	}
+
+	unknownStatLabels.labels[label] = true
+	logger.Info("unknown stat label", "label", label)
+}
+
+// resetUnknownStatLabels forgets the labels that have been logged, so they are logged again.
+func resetUnknownStatLabels() {
+	unknownStatLabels.Lock()
+	defer unknownStatLabels.Unlock()
+
+	unknownStatLabels.labels = map[string]bool{}
+}
+
+// NormalizeStat returns the key and canonical name of the stat with the given label (as shown on the career page).
+// Labels missing from STAT_DEFINITIONS (i.e. hero specific stats) are passed through as the name, with a key made
+// from the label itself, so "Games Won" would give "games_won". Those keys are only as stable as the label
+// (i.e. "Dragonblade Kill" and "Dragonblade Kills" give different keys), which known reports as false.
+func NormalizeStat(label string) (key string, name string, known bool) {
+	label = TrimToString(label)
+	if definition, ok := statDefinitionsByLabel[strings.ToLower(label)]; ok {
+		return definition.Key, definition.Name, true
+	}
+
+	return ToSnakeCase(label), label, false
+}
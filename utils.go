@@ -5,10 +5,13 @@ import (
 	"strings"
 	"net/http"
 	"encoding/json"
+	"regexp"
+	"unicode"
 )
 
 type ErrorResponse struct {
-	Errors []string `json:"errors"`
+	Errors    []string `json:"errors"`
+	RequestID string   `json:"request_id,omitempty"`
 }
 
 // TrimToInt returns an cleaned int given a string.
@@ -27,6 +30,28 @@ func TrimToString(s string) string {
 	return string(strings.TrimSpace(s))
 }
 
+// ToSnakeCase returns the string in lowercase, with every run of characters other than letters and digits replaced by
+// a single underscore (i.e. "Eliminations - Most in Game" gives "eliminations_most_in_game").
+func ToSnakeCase(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "_")
+}
+
+// backgroundImageURL matches the URL of a "background-image:url({URL})" style, with or without quotes.
+var backgroundImageURL = regexp.MustCompile(`background-image:\s*url\(\s*['"]?([^'")]+)['"]?\s*\)`)
+
+// GetBackgroundImageURL returns the URL of the background image set in the style attribute, or an empty string if
+// there is none.
+func GetBackgroundImageURL(style string) string {
+	match := backgroundImageURL.FindStringSubmatch(style)
+	if match == nil {
+		return ""
+	}
+	return TrimToString(match[1])
+}
+
 // TrimToFloat returns an float given a string.
 // Various "cleaning operations" include stripping of whitespace and removal of commas.
 func TrimToFloat(s string) float64 {
@@ -70,7 +95,55 @@ func CalculateStars(level int) int {
 	return stars
 }
 
+// CalculatePrestigeTier returns the name of the tier (the color of the level's border) the player is in according to
+// their (true) level. The tiers follow the same 600 level steps as CalculateStars, with every level above 2400 in
+// Diamond. A level of 0 (unknown) has no tier.
+func CalculatePrestigeTier(level int) string {
+	switch {
+	case level > 2400:
+		return "Diamond"
+	case level > 1800:
+		return "Platinum"
+	case level > 1200:
+		return "Gold"
+	case level > 600:
+		return "Silver"
+	case level > 0:
+		return "Bronze"
+	}
+	return ""
+}
+
+// CalculateSkillTier returns the competitive tier (see SKILL_TIERS) of the given skill rating.
+// A skill rating of 0 (unranked) has no tier.
+func CalculateSkillTier(skillRating int) string {
+	tier := ""
+	for _, t := range SKILL_TIERS {
+		if skillRating >= t.MinSkillRating {
+			tier = t.Name
+		}
+	}
+	return tier
+}
+
+// GetRoleFromLabel returns the role (see ROLES) named at the start of the label (i.e. "Tank Skill Rating" gives
+// "tank"). Unknown roles are returned as the lowercase label.
+func GetRoleFromLabel(label string) string {
+	label = strings.ToLower(TrimToString(label))
+	for _, role := range ROLES {
+		if strings.HasPrefix(label, role) {
+			return role
+		}
+	}
+	return label
+}
+
 func ReturnErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, res ErrorResponse) {
+	// Include the request ID, so the caller can refer to the request when reporting the error.
+	if res.RequestID == "" {
+		res.RequestID = requestIDFromContext(r.Context())
+	}
+
 	response, err := json.Marshal(res)
 	if err != nil {
 		panic(err)
@@ -81,7 +154,39 @@ func ReturnErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int,
 		return
 	}
 
-	w.WriteHeader(statusCode)
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
 	w.Write(response)
 }
+
+// TrimToSeconds returns the number of seconds in a duration string given a string.
+// Durations on the career page come in one of two formats: "12 hours" / "45 minutes" / "30 seconds", or "HH:MM:SS" /
+// "MM:SS". Unknown formats return 0.
+func TrimToSeconds(s string) float64 {
+	s = strings.ToLower(TrimToString(s))
+
+	if strings.Contains(s, ":") {
+		seconds := 0.0
+		for _, part := range strings.Split(s, ":") {
+			seconds = seconds*60 + TrimToFloat(part)
+		}
+		return seconds
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0
+	}
+
+	value := TrimToFloat(fields[0])
+	switch {
+	case strings.HasPrefix(fields[1], "hour"):
+		return value * 60 * 60
+	case strings.HasPrefix(fields[1], "minute"):
+		return value * 60
+	case strings.HasPrefix(fields[1], "second"):
+		return value
+	}
+
+	return 0
+}